@@ -0,0 +1,46 @@
+package chain
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff configures delays between reconnect attempts
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+	// MaxAttempts is the number of redials before giving up, zero means retry forever
+	MaxAttempts int
+}
+
+// Delay returns the exponential delay for given attempt with jitter applied.
+// The result is picked uniformly from the upper half of the exponential step,
+// so concurrent operators never redial in lockstep.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := b.Min
+	for i := 0; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
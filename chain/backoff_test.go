@@ -0,0 +1,34 @@
+package chain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Min: time.Second, Max: 30 * time.Second}
+	tests := []struct {
+		attempt int
+		step    time.Duration
+	}{
+		{attempt: 0, step: time.Second},
+		{attempt: 1, step: 2 * time.Second},
+		{attempt: 3, step: 8 * time.Second},
+		{attempt: 5, step: 30 * time.Second},
+		{attempt: 100, step: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			d := b.Delay(tt.attempt)
+			if d < tt.step/2 || d > tt.step {
+				t.Fatalf("Delay(%d) = %s, want between %s and %s", tt.attempt, d, tt.step/2, tt.step)
+			}
+		}
+	}
+}
+
+func TestBackoffDelayZero(t *testing.T) {
+	if d := (Backoff{}).Delay(3); d != 0 {
+		t.Fatalf("Delay without backoff = %s, want 0", d)
+	}
+}
@@ -0,0 +1,252 @@
+package chain
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+// State of the RPC connection
+type State int32
+
+const (
+	// Connected means the RPC endpoint is reachable
+	Connected State = iota
+	// Reconnecting means the connection dropped and is being redialed
+	Reconnecting
+	// Disconnected means the client was closed or gave up reconnecting
+	Disconnected
+)
+
+func (s State) String() string {
+	switch s {
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	case Disconnected:
+		return "disconnected"
+	default:
+		return "unknown"
+	}
+}
+
+// Client is an Ethereum client that can redial its RPC endpoint.
+// Contract bindings created with it keep working after a reconnect.
+type Client struct {
+	url     string
+	backoff Backoff
+	logger  *log.Logger
+
+	mu     sync.RWMutex
+	client *ethclient.Client
+
+	// reconnectMu makes concurrent callers share a single redial
+	reconnectMu sync.Mutex
+	generation  atomic.Uint64
+	state       atomic.Int32
+}
+
+// Dial connects to the RPC endpoint at given url
+func Dial(ctx context.Context, url string, backoff Backoff, logger *log.Logger) (*Client, error) {
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while connecting to Ethereum client")
+	}
+
+	c := &Client{
+		url:     url,
+		backoff: backoff,
+		logger:  logger,
+		client:  client,
+	}
+	c.state.Store(int32(Connected))
+	return c, nil
+}
+
+// State returns the current connection state
+func (c *Client) State() State {
+	return State(c.state.Load())
+}
+
+// Generation is incremented on every successful reconnect
+func (c *Client) Generation() uint64 {
+	return c.generation.Load()
+}
+
+func (c *Client) setState(state State) {
+	if prev := State(c.state.Swap(int32(state))); prev != state {
+		c.logger.Printf("RPC connection state changed from %s to %s\n", prev, state)
+	}
+}
+
+// Reconnect closes the current connection and redials with exponential backoff.
+// Callers that observed a failure on generation gen share one redial: if the
+// connection was already replaced since then Reconnect returns immediately.
+func (c *Client) Reconnect(ctx context.Context, gen uint64) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	if c.generation.Load() != gen {
+		return nil
+	}
+
+	c.setState(Reconnecting)
+	c.eth().Close()
+
+	for attempt := 0; ; attempt++ {
+		client, err := ethclient.DialContext(ctx, c.url)
+		if err == nil {
+			// Dial is lazy for some transports, make sure the node actually answers
+			if _, err = client.BlockNumber(ctx); err != nil {
+				client.Close()
+			}
+		}
+		if err == nil {
+			c.mu.Lock()
+			c.client = client
+			c.mu.Unlock()
+
+			c.generation.Add(1)
+			c.setState(Connected)
+			return nil
+		}
+
+		if c.backoff.MaxAttempts > 0 && attempt+1 >= c.backoff.MaxAttempts {
+			c.setState(Disconnected)
+			return errors.Wrapf(err, "Error while reconnecting after %d attempts", attempt+1)
+		}
+
+		delay := c.backoff.Delay(attempt)
+		c.logger.Printf("Reconnect attempt %d failed: %v, retrying in %s\n", attempt+1, err, delay)
+		if err := sleep(ctx, delay); err != nil {
+			c.setState(Disconnected)
+			return err
+		}
+	}
+}
+
+// Close closes the underlying connection
+func (c *Client) Close() {
+	c.setState(Disconnected)
+	c.eth().Close()
+}
+
+func (c *Client) eth() *ethclient.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// ChainID retrieves the current chain ID for transaction replay protection.
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.eth().ChainID(ctx)
+}
+
+// NetworkID returns the network ID.
+func (c *Client) NetworkID(ctx context.Context) (*big.Int, error) {
+	return c.eth().NetworkID(ctx)
+}
+
+// BlockNumber returns the most recent block number.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.eth().BlockNumber(ctx)
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.eth().HeaderByNumber(ctx, number)
+}
+
+// HeaderByHash returns the block header with the given hash.
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return c.eth().HeaderByHash(ctx, hash)
+}
+
+// TransactionByHash returns the transaction with the given hash.
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return c.eth().TransactionByHash(ctx, hash)
+}
+
+// TransactionReceipt returns the receipt of a transaction by transaction hash.
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return c.eth().TransactionReceipt(ctx, txHash)
+}
+
+// SubscribeNewHead subscribes to notifications about the current blockchain head.
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return c.eth().SubscribeNewHead(ctx, ch)
+}
+
+// BalanceAt returns the wei balance of the given account.
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return c.eth().BalanceAt(ctx, account, blockNumber)
+}
+
+// CodeAt returns the contract code of the given account.
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.eth().CodeAt(ctx, account, blockNumber)
+}
+
+// NonceAt returns the account nonce of the given account.
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.eth().NonceAt(ctx, account, blockNumber)
+}
+
+// FilterLogs executes a filter query.
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return c.eth().FilterLogs(ctx, q)
+}
+
+// SubscribeFilterLogs subscribes to the results of a streaming filter query.
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return c.eth().SubscribeFilterLogs(ctx, q, ch)
+}
+
+// PendingCodeAt returns the contract code of the given account in the pending state.
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return c.eth().PendingCodeAt(ctx, account)
+}
+
+// PendingNonceAt returns the account nonce of the given account in the pending state.
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.eth().PendingNonceAt(ctx, account)
+}
+
+// CallContract executes a message call transaction.
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.eth().CallContract(ctx, msg, blockNumber)
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.eth().SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap after 1559.
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.eth().SuggestGasTipCap(ctx)
+}
+
+// FeeHistory retrieves the fee market history.
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return c.eth().FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return c.eth().EstimateGas(ctx, msg)
+}
+
+// SendTransaction injects a signed transaction into the pending pool for execution.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.eth().SendTransaction(ctx, tx)
+}
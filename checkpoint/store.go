@@ -0,0 +1,62 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/internal/fsutil"
+)
+
+// Store persists the last fully processed block on disk
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+type state struct {
+	LastProcessedBlock uint64 `json:"lastProcessedBlock"`
+}
+
+// New returns a new Store backed by file at given path
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the last fully processed block, ok is false if nothing was saved yet
+func (s *Store) Load() (block uint64, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error while reading checkpoint file")
+	}
+
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return 0, false, errors.Wrap(err, "Error while decoding checkpoint file")
+	}
+	return st.LastProcessedBlock, true, nil
+}
+
+// Save atomically replaces the checkpoint with given block
+func (s *Store) Save(block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(state{LastProcessedBlock: block})
+	if err != nil {
+		return errors.Wrap(err, "Error while encoding checkpoint")
+	}
+
+	if err := fsutil.WriteFileAtomic(s.path, data); err != nil {
+		return errors.Wrap(err, "Error while saving checkpoint file")
+	}
+	return nil
+}
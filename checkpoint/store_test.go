@@ -0,0 +1,35 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	store := New(path)
+
+	if _, ok, err := store.Load(); err != nil || ok {
+		t.Fatalf("Load of a missing file = %v, %v, want nothing saved", ok, err)
+	}
+	for _, block := range []uint64{0, 42, 7} {
+		if err := store.Save(block); err != nil {
+			t.Fatalf("Save(%d): %v", block, err)
+		}
+		got, ok, err := New(path).Load()
+		if err != nil || !ok || got != block {
+			t.Fatalf("Load = %d, %v, %v, want %d", got, ok, err, block)
+		}
+	}
+}
+
+func TestStoreCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := New(path).Load(); err == nil {
+		t.Fatal("Load of a corrupt file succeeded")
+	}
+}
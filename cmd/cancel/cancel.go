@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Cancels a pending transaction by replacing it with a zero-value transfer to self
+func main() {
+	logger := log.Default()
+
+	nonceFlag := flag.Int64("nonce", -1, "nonce of the pending transaction to cancel")
+	flag.Parse()
+	if *nonceFlag < 0 {
+		logger.Fatalf("Missing -nonce\n")
+	}
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+
+	client, err := setup.Dial(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while creating transaction manager: %v\n", err)
+	}
+	defer closeJournal()
+
+	account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
+	if err != nil {
+		logger.Fatalf("Error while creating signer: %v\n", err)
+	}
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		logger.Fatalf("Error while getting chain id: %v\n", err)
+	}
+
+	opts := signer.TransactOpts(context.Background(), account, chainID)
+	result, err := txm.Cancel(context.Background(), opts, uint64(*nonceFlag))
+	if err != nil {
+		logger.Fatalf("Error while cancelling transaction: %v\n", err)
+	}
+	logger.Printf("Cancel transaction %s is %s\n", result.Tx.Hash().Hex(), result.Status)
+}
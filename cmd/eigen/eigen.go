@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	ierc20 "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IERC20"
+	strategymanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StrategyManager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+const usage = `Usage:
+  eigen approve -token 0x... -amount wei [-spender 0x...]
+  eigen deposit -strategy 0x... -amount wei
+  eigen sign-delegation -staker 0x... [-operator 0x...] [-expiry 1h]
+  eigen delegate -operator 0x... [-approver-signature 0x... -approver-salt 0x... -approver-expiry unix]
+  eigen undelegate
+  eigen queue-withdrawal [-strategies 0x...,...] [-shares wei,...]
+  eigen complete-withdrawal [-from-block 0] [-receive-as-tokens=true]
+
+Every subcommand takes -index to act from a wallet derived from MNEMONIC_FILE.
+`
+
+// Staker side of EigenLayer: deposits into strategies, delegates to operators
+// and withdraws again, so test networks can get operators above the minimum weight
+func main() {
+	logger := log.Default()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	index := flags.Int("index", -1, "index of the wallet derived from MNEMONIC_FILE, HD_INDEX when not set")
+	token := flags.String("token", "", "ERC-20 token to approve")
+	spender := flags.String("spender", "", "spender to approve, the strategy manager when empty")
+	amount := flags.String("amount", "", "amount in base units of the token")
+	strategy := flags.String("strategy", "", "strategy to deposit into")
+	staker := flags.String("staker", "", "staker the delegation approval is for")
+	operator := flags.String("operator", "", "operator to delegate to, the signing key itself for sign-delegation when empty")
+	expiry := flags.Duration("expiry", time.Hour, "how long the delegation approval stays valid")
+	approverSignature := flags.String("approver-signature", "", "delegation approver signature from sign-delegation")
+	approverSalt := flags.String("approver-salt", "", "delegation approver salt from sign-delegation")
+	approverExpiry := flags.Uint64("approver-expiry", 0, "delegation approver expiry from sign-delegation")
+	strategies := flags.String("strategies", "", "comma separated strategies to withdraw from, all when empty")
+	shares := flags.String("shares", "", "comma separated shares to withdraw per strategy, all when empty")
+	fromBlock := flags.Uint64("from-block", 0, "block to look for queued withdrawals from")
+	receiveAsTokens := flags.Bool("receive-as-tokens", true, "receive tokens instead of shares that are delegated again")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		logger.Fatalf("Error while parsing flags: %v\n", err)
+	}
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+	if *index >= 0 {
+		if err := cfg.UseWallet(uint32(*index)); err != nil {
+			logger.Fatalf("Error while selecting wallet: %v\n", err)
+		}
+	}
+
+	client, err := setup.Dial(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger, delegationmanager.ContractDelegationManagerMetaData, strategymanager.ContractStrategyManagerMetaData, ierc20.ContractIERC20MetaData)
+	if err != nil {
+		logger.Fatalf("Error while creating transaction manager: %v\n", err)
+	}
+	defer closeJournal()
+
+	eigenService, err := eigen.New(cfg.DelegationManagerAddress, client, logger, txm)
+	if err != nil {
+		logger.Fatalf("Error while creating eigen smart contract service: %v\n", err)
+	}
+
+	account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
+	if err != nil {
+		logger.Fatalf("Error while creating signer: %v\n", err)
+	}
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "approve":
+		spenderAddress := address(logger, "spender", *spender)
+		if *spender == "" {
+			if spenderAddress, err = eigenService.StrategyManager(ctx); err != nil {
+				logger.Fatalf("Error while approving token: %v\n", err)
+			}
+		}
+		if err := eigenService.Approve(ctx, account, required(logger, "token", *token), spenderAddress, parseAmount(logger, *amount)); err != nil {
+			logger.Fatalf("Error while approving token: %v\n", err)
+		}
+
+	case "deposit":
+		if _, err := eigenService.Deposit(ctx, account, required(logger, "strategy", *strategy), parseAmount(logger, *amount)); err != nil {
+			logger.Fatalf("Error while depositing into strategy: %v\n", err)
+		}
+
+	case "sign-delegation":
+		operatorAddress := address(logger, "operator", *operator)
+		if *operator == "" {
+			operatorAddress = account.Address()
+		}
+		approval, err := eigenService.SignDelegationApproval(ctx, account, required(logger, "staker", *staker), operatorAddress, *expiry)
+		if err != nil {
+			logger.Fatalf("Error while signing delegation approval: %v\n", err)
+		}
+		fmt.Printf("-approver-signature %s -approver-salt %s -approver-expiry %s\n", hexutil.Encode(approval.Signature), hexutil.Encode(approval.Salt[:]), approval.Expiry)
+
+	case "delegate":
+		var approval *eigen.DelegationApproval
+		if *approverSignature != "" {
+			approval = parseApproval(logger, *approverSignature, *approverSalt, *approverExpiry)
+		}
+		if err := eigenService.DelegateTo(ctx, account, required(logger, "operator", *operator), approval); err != nil {
+			logger.Fatalf("Error while delegating: %v\n", err)
+		}
+
+	case "undelegate":
+		if _, err := eigenService.Undelegate(ctx, account); err != nil {
+			logger.Fatalf("Error while undelegating: %v\n", err)
+		}
+
+	case "queue-withdrawal":
+		var strategyAddresses []common.Address
+		for _, s := range split(*strategies) {
+			strategyAddresses = append(strategyAddresses, address(logger, "strategy", s))
+		}
+		var amounts []*big.Int
+		for _, s := range split(*shares) {
+			amounts = append(amounts, parseAmount(logger, s))
+		}
+		if _, err := eigenService.QueueWithdrawal(ctx, account, strategyAddresses, amounts); err != nil {
+			logger.Fatalf("Error while queueing withdrawal: %v\n", err)
+		}
+
+	case "complete-withdrawal":
+		withdrawals, err := eigenService.PendingWithdrawals(ctx, account.Address(), *fromBlock, cfg.BackfillChunkSize)
+		if err != nil {
+			logger.Fatalf("Error while looking up withdrawals: %v\n", err)
+		}
+		if len(withdrawals) == 0 {
+			logger.Printf("No pending withdrawals of %s since block %d\n", account.Address().Hex(), *fromBlock)
+			return
+		}
+		waiting, err := eigenService.CompleteWithdrawals(ctx, account, withdrawals, *receiveAsTokens)
+		if err != nil {
+			logger.Fatalf("Error while completing withdrawals: %v\n", err)
+		}
+		if len(waiting) > 0 {
+			logger.Printf("%d withdrawals are not ready yet, run again later\n", len(waiting))
+		}
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// required parses the address given for flag name, which must be set
+func required(logger *log.Logger, name, value string) common.Address {
+	if value == "" {
+		logger.Fatalf("-%s is required\n", name)
+	}
+	return address(logger, name, value)
+}
+
+// address parses the address given for flag name, zero when it is empty
+func address(logger *log.Logger, name, value string) common.Address {
+	if value == "" {
+		return common.Address{}
+	}
+	if !common.IsHexAddress(value) {
+		logger.Fatalf("Invalid %s address %q\n", name, value)
+	}
+	return common.HexToAddress(value)
+}
+
+// parseAmount parses a positive decimal amount in base units
+func parseAmount(logger *log.Logger, value string) *big.Int {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() <= 0 {
+		logger.Fatalf("Invalid amount %q, it must be a positive number in base units\n", value)
+	}
+	return amount
+}
+
+// parseApproval parses the delegation approval printed by sign-delegation
+func parseApproval(logger *log.Logger, signature, salt string, expiry uint64) *eigen.DelegationApproval {
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		logger.Fatalf("Invalid approver signature: %v\n", err)
+	}
+	saltBytes, err := hexutil.Decode(salt)
+	if err != nil || len(saltBytes) != 32 {
+		logger.Fatalf("Invalid approver salt %q, it must be 32 bytes of hex\n", salt)
+	}
+	if expiry == 0 {
+		logger.Fatalf("-approver-expiry is required with -approver-signature\n")
+	}
+
+	approval := &eigen.DelegationApproval{Signature: sig, Expiry: new(big.Int).SetUint64(expiry)}
+	copy(approval.Salt[:], saltBytes)
+	return approval
+}
+
+// split returns the non-empty items of a comma separated list
+func split(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
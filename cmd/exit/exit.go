@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/ethereum/go-ethereum/common"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Deregisters a stopped operator from the stake registry and the AVS
+func main() {
+	logger := log.Default()
+
+	index := flag.Int("index", -1, "index of the wallet derived from MNEMONIC_FILE, HD_INDEX when not set")
+	dryRun := flag.Bool("dry-run", false, "only print the steps that would be taken")
+	undelegate := flag.String("undelegate", "", "comma separated stakers delegated to the operator to undelegate")
+	flag.Parse()
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+	if *index >= 0 {
+		if err := cfg.UseWallet(uint32(*index)); err != nil {
+			logger.Fatalf("Error while selecting wallet: %v\n", err)
+		}
+	}
+
+	exitConfig := eigen.ExitConfig{DryRun: *dryRun}
+	for _, staker := range strings.Split(*undelegate, ",") {
+		if staker = strings.TrimSpace(staker); staker == "" {
+			continue
+		}
+		if !common.IsHexAddress(staker) {
+			logger.Fatalf("Invalid staker address %q\n", staker)
+		}
+		exitConfig.Undelegate = append(exitConfig.Undelegate, common.HexToAddress(staker))
+	}
+
+	client, err := setup.Dial(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger, helloworld.HelloWorldMetaData, delegationmanager.ContractDelegationManagerMetaData, stakeregistry.ECDSAStakeRegistryMetaData)
+	if err != nil {
+		logger.Fatalf("Error while creating transaction manager: %v\n", err)
+	}
+	defer closeJournal()
+
+	eigenService, err := eigen.New(cfg.DelegationManagerAddress, client, logger, txm)
+	if err != nil {
+		logger.Fatalf("Error while creating eigen smart contract service: %v\n", err)
+	}
+	avs, err := eigenService.AVS(context.Background(), common.HexToAddress(cfg.HelloWorldAddress))
+	if err != nil {
+		logger.Fatalf("Error while looking up AVS contracts: %v\n", err)
+	}
+
+	account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
+	if err != nil {
+		logger.Fatalf("Error while creating signer: %v\n", err)
+	}
+
+	if _, err := eigenService.Exit(context.Background(), account, avs, exitConfig); err != nil {
+		logger.Fatalf("Error while exiting the AVS: %v\n", err)
+	}
+	if !*dryRun {
+		logger.Printf("Operator %s left the AVS\n", account.Address().Hex())
+	}
+}
@@ -0,0 +1,55 @@
+// Package setup builds the chain client and transaction manager the commands share
+package setup
+
+import (
+	"context"
+	"log"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/chain"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/fees"
+	"github.com/patiee/avs-go-operator/gas"
+	"github.com/patiee/avs-go-operator/nonce"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// Dial connects to the configured websocket endpoint, reconnecting with the
+// configured backoff when the connection drops
+func Dial(ctx context.Context, cfg *config.Config, logger *log.Logger) (*chain.Client, error) {
+	backoff := chain.Backoff{
+		Min:         cfg.ReconnectMinBackoff,
+		Max:         cfg.ReconnectMaxBackoff,
+		MaxAttempts: cfg.ReconnectMaxAttempts,
+	}
+	return chain.Dial(ctx, cfg.WebsocketURL(), backoff, logger)
+}
+
+// TxManager returns a transaction manager sending through client with the
+// configured fees and gas limits. Methods of the contracts of metadata are
+// known to the gas estimator by name. Transactions are recorded in
+// cfg.TxJournalFile unless it is empty, closeJournal closes it again.
+func TxManager(client *chain.Client, cfg *config.Config, logger *log.Logger, metadata ...*bind.MetaData) (txm *txmgr.Manager, closeJournal func(), err error) {
+	feeStrategy, err := fees.New(cfg.FeeConfig(), client)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error while creating fee strategy")
+	}
+	estimator := gas.New(client, logger, cfg.GasConfig())
+	for _, m := range metadata {
+		if err := estimator.Register(m); err != nil {
+			return nil, nil, errors.Wrap(err, "Error while registering contract abi")
+		}
+	}
+
+	var journal *txmgr.Journal
+	closeJournal = func() {}
+	if cfg.TxJournalFile != "" {
+		if journal, err = txmgr.OpenJournal(cfg.TxJournalFile); err != nil {
+			return nil, nil, err
+		}
+		closeJournal = func() { journal.Close() }
+	}
+	return txmgr.New(client, nonce.New(client, logger), feeStrategy, estimator, journal, logger, cfg.TxManagerConfig()), closeJournal, nil
+}
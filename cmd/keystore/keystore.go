@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/patiee/avs-go-operator/keys"
+)
+
+const usage = `Usage:
+  keystore new [-dir keystore] [-passphrase-file file]
+  keystore import [-dir keystore] [-passphrase-file file] [-key-file file]
+  keystore mnemonic [-mnemonic-file file]
+  keystore derive [-mnemonic-file file] [-hd-path path] [-index 0] [-count 1]
+`
+
+// Creates V3 keystores, either with a new key or with an existing hex key, and
+// BIP-39 mnemonics with the addresses of the wallets derived from them
+func main() {
+	logger := log.Default()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := flags.String("dir", "keystore", "directory the keystore file is written to")
+	passphraseFile := flags.String("passphrase-file", "", "file holding the passphrase, prompted for when empty")
+	keyFile := flags.String("key-file", "", "file holding the hex private key to import, prompted for when empty")
+	mnemonicFile := flags.String("mnemonic-file", "", "file holding the mnemonic, written by mnemonic and prompted for by derive when empty")
+	hdPath := flags.String("hd-path", keys.DefaultHDPath.String(), "derivation path the wallet index is appended to")
+	index := flags.Uint("index", 0, "index of the first derived wallet")
+	count := flags.Uint("count", 1, "number of derived wallets")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		logger.Fatalf("Error while parsing flags: %v\n", err)
+	}
+
+	switch os.Args[1] {
+	case "new":
+		passphrase, err := keys.NewPassphrase(*passphraseFile)
+		if err != nil {
+			logger.Fatalf("Error while reading passphrase: %v\n", err)
+		}
+		account, err := keys.Create(*dir, passphrase)
+		if err != nil {
+			logger.Fatalf("Error while creating keystore: %v\n", err)
+		}
+		logger.Printf("Created key %s in %s\n", account.Address.Hex(), account.URL.Path)
+
+	case "import":
+		hexKey, err := readHexKey(*keyFile)
+		if err != nil {
+			logger.Fatalf("Error while reading private key: %v\n", err)
+		}
+		pk, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+		if err != nil {
+			logger.Fatalf("Error while parsing private key: %v\n", err)
+		}
+		passphrase, err := keys.NewPassphrase(*passphraseFile)
+		if err != nil {
+			logger.Fatalf("Error while reading passphrase: %v\n", err)
+		}
+		account, err := keys.Import(*dir, pk, passphrase)
+		if err != nil {
+			logger.Fatalf("Error while importing key: %v\n", err)
+		}
+		logger.Printf("Imported key %s into %s\n", account.Address.Hex(), account.URL.Path)
+
+	case "mnemonic":
+		mnemonic, err := keys.NewMnemonic()
+		if err != nil {
+			logger.Fatalf("Error while creating mnemonic: %v\n", err)
+		}
+		if *mnemonicFile == "" {
+			fmt.Println(mnemonic)
+			return
+		}
+		if err := writeMnemonic(*mnemonicFile, mnemonic); err != nil {
+			logger.Fatalf("Error while writing mnemonic: %v\n", err)
+		}
+		logger.Printf("Written mnemonic to %s\n", *mnemonicFile)
+
+	case "derive":
+		base, err := accounts.ParseDerivationPath(*hdPath)
+		if err != nil {
+			logger.Fatalf("Error while parsing derivation path: %v\n", err)
+		}
+		mnemonic, err := readMnemonic(*mnemonicFile)
+		if err != nil {
+			logger.Fatalf("Error while reading mnemonic: %v\n", err)
+		}
+		for i := *index; i < *index+*count; i++ {
+			path, err := keys.HDPath(base, uint32(i))
+			if err != nil {
+				logger.Fatalf("Error while deriving wallet %d: %v\n", i, err)
+			}
+			pk, err := keys.Derive(mnemonic, path)
+			if err != nil {
+				logger.Fatalf("Error while deriving wallet %d: %v\n", i, err)
+			}
+			fmt.Printf("%d\t%s\t%s\n", i, path, crypto.PubkeyToAddress(pk.PublicKey).Hex())
+		}
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func readHexKey(file string) (string, error) {
+	if file == "" {
+		return keys.Prompt("Hex private key: ")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeMnemonic creates file with mnemonic, an existing file is never overwritten
+// so the seed of funded wallets cannot be lost
+func writeMnemonic(file, mnemonic string) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(mnemonic + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readMnemonic(file string) (string, error) {
+	if file == "" {
+		return keys.Prompt("Mnemonic: ")
+	}
+	return keys.ReadMnemonic(file)
+}
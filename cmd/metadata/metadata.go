@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/metadata"
+)
+
+const usage = `Usage:
+  metadata build [-dir operator-metadata] [-logo-file logo.png]
+  metadata validate [-file metadata.json | -uri url]
+  metadata serve [-dir operator-metadata] [-addr :8080]
+`
+
+// Builds the operator metadata JSON from .env, validates it against the
+// EigenLayer rules and serves it for local networks
+func main() {
+	logger := log.Default()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := flags.String("dir", "operator-metadata", "directory metadata.json is written to and served from")
+	logoFile := flags.String("logo-file", "", "PNG logo copied next to metadata.json, OPERATOR_LOGO must point at where it is served")
+	file := flags.String("file", "", "metadata file to validate")
+	uri := flags.String("uri", "", "metadata URI to validate, OPERATOR_METADATA_URI when empty")
+	addr := flags.String("addr", ":8080", "address the metadata server listens on")
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		logger.Fatalf("Error while parsing flags: %v\n", err)
+	}
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+	opts := metadata.Options{AllowLocal: cfg.MetadataAllowLocal}
+
+	switch os.Args[1] {
+	case "build":
+		if *logoFile != "" {
+			if opts.Logo, err = os.ReadFile(*logoFile); err != nil {
+				logger.Fatalf("Error while reading logo: %v\n", err)
+			}
+		}
+		m := cfg.Metadata()
+		if err := metadata.Validate(context.Background(), m, opts); err != nil {
+			logger.Fatalf("Error while validating metadata: %v\n", err)
+		}
+		data, err := metadata.Encode(m)
+		if err != nil {
+			logger.Fatalf("Error while encoding metadata: %v\n", err)
+		}
+
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			logger.Fatalf("Error while creating metadata directory: %v\n", err)
+		}
+		path := filepath.Join(*dir, "metadata.json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			logger.Fatalf("Error while writing metadata: %v\n", err)
+		}
+		if opts.Logo != nil {
+			if err := os.WriteFile(filepath.Join(*dir, "logo.png"), opts.Logo, 0o644); err != nil {
+				logger.Fatalf("Error while writing logo: %v\n", err)
+			}
+		}
+		logger.Printf("Written metadata to %s, set OPERATOR_METADATA_HASH=%s\n", path, metadata.Hash(data).Hex())
+
+	case "validate":
+		if *file != "" {
+			data, err := os.ReadFile(*file)
+			if err != nil {
+				logger.Fatalf("Error while reading metadata: %v\n", err)
+			}
+			m, err := metadata.Decode(data)
+			if err != nil {
+				logger.Fatalf("Error while decoding metadata: %v\n", err)
+			}
+			if err := metadata.Validate(context.Background(), m, opts); err != nil {
+				logger.Fatalf("Error while validating metadata: %v\n", err)
+			}
+			logger.Printf("Metadata in %s is valid, hash: %s\n", *file, metadata.Hash(data).Hex())
+			return
+		}
+
+		target := *uri
+		hash := common.Hash{}
+		if target == "" {
+			target, hash = cfg.OperatorMetadataURI, cfg.OperatorMetadataHash
+		}
+		actual, err := metadata.Check(context.Background(), target, hash, opts)
+		if err != nil {
+			logger.Fatalf("Error while validating metadata: %v\n", err)
+		}
+		logger.Printf("Metadata at %s is valid, hash: %s\n", target, actual.Hex())
+
+	case "serve":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := metadata.Serve(ctx, *addr, *dir, logger); err != nil {
+			logger.Fatalf("Error while serving metadata: %v\n", err)
+		}
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
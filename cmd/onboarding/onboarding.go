@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/onboarding"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Shows the onboarding steps of the operator and which one it is at, without taking any
+func main() {
+	logger := log.Default()
+
+	index := flag.Int("index", -1, "index of the wallet derived from MNEMONIC_FILE, HD_INDEX when not set")
+	operator := flag.String("operator", "", "operator to show, the configured operator key when empty")
+	flag.Parse()
+
+	if *operator != "" && !common.IsHexAddress(*operator) {
+		logger.Fatalf("Invalid operator address %q\n", *operator)
+	}
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+	if *index >= 0 {
+		if err := cfg.UseWallet(uint32(*index)); err != nil {
+			logger.Fatalf("Error while selecting wallet: %v\n", err)
+		}
+	}
+
+	client, err := setup.Dial(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	// Only reads, so no transaction manager is needed
+	eigenService, err := eigen.New(cfg.DelegationManagerAddress, client, logger, nil)
+	if err != nil {
+		logger.Fatalf("Error while creating eigen smart contract service: %v\n", err)
+	}
+
+	address := common.HexToAddress(*operator)
+	if *operator == "" {
+		account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
+		if err != nil {
+			logger.Fatalf("Error while creating signer: %v\n", err)
+		}
+		address = account.Address()
+	}
+
+	avs, err := eigenService.AVS(context.Background(), common.HexToAddress(cfg.HelloWorldAddress))
+	if err != nil {
+		logger.Fatalf("Error while looking up AVS contracts: %v\n", err)
+	}
+	progress, err := eigenService.Onboarding(context.Background(), address, avs, cfg.OnboardingConfig())
+	if err != nil {
+		logger.Fatalf("Error while getting onboarding progress: %v\n", err)
+	}
+
+	fmt.Printf("Onboarding of %s\n", progress.Operator.Hex())
+	for i, step := range onboarding.Steps {
+		status := "pending"
+		switch {
+		case slices.Contains(progress.Completed, step):
+			status = "done"
+		case slices.Contains(progress.Skipped, step):
+			status = "skipped, optional"
+		case step == progress.Step:
+			status = "current"
+		}
+		fmt.Printf("  %d. %-32s %s\n", i+1, step.Description(), status)
+	}
+
+	if progress.Step == onboarding.Done {
+		fmt.Println("Onboarding is complete, the operator processes tasks")
+		return
+	}
+	fmt.Printf("At step %d of %d: %s\n", progress.Number(), len(onboarding.Steps), progress.Step.Description())
+	if progress.Waiting != "" {
+		fmt.Printf("Waiting: %s\n", progress.Waiting)
+	}
+	if progress.LastError != "" {
+		fmt.Printf("Last error at %s: %s\n", progress.UpdatedAt.Format("2006-01-02 15:04:05"), progress.LastError)
+	}
+}
@@ -1,67 +1,271 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"flag"
 	"log"
-	"math/big"
-	"strconv"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/joho/godotenv"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/checkpoint"
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
 	"github.com/patiee/avs-go-operator/contract"
 	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/stake"
 )
 
 func main() {
 	logger := log.Default()
 
+	index := flag.Int("index", -1, "index of the wallet derived from MNEMONIC_FILE, HD_INDEX when not set")
+	flag.Parse()
+
 	logger.Print("Starting go-operator")
-	defer logger.Print("go-operator exited")
+	if err := run(logger, *index); err != nil {
+		logger.Fatalf("%v\n", err)
+	}
+	logger.Print("go-operator exited")
+}
+
+// run starts the operator with the wallet at index, or HD_INDEX when index is
+// negative, and returns once it stopped so deferred cleanup always runs
+func run(logger *log.Logger, index int) error {
+	cfg, err := config.Load(".env")
+	if err != nil {
+		return errors.Wrap(err, "Error while loading config")
+	}
+	if index >= 0 {
+		if err := cfg.UseWallet(uint32(index)); err != nil {
+			return errors.Wrap(err, "Error while selecting wallet")
+		}
+	}
 
-	env, err := godotenv.Read(".env")
+	client, err := setup.Dial(context.Background(), cfg, logger)
 	if err != nil {
-		logger.Fatalf("Error while reading .env file: %v\n", err)
+		return errors.Wrap(err, "Error while connecting to Ethereum client")
 	}
+	defer client.Close()
 
-	client, err := ethclient.Dial(fmt.Sprintf("ws://%s", env["RPC_URL"]))
+	// Shared by every service so transactions from the same key never collide
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger, helloworld.HelloWorldMetaData, delegationmanager.ContractDelegationManagerMetaData, stakeregistry.ECDSAStakeRegistryMetaData)
 	if err != nil {
-		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+		return errors.Wrap(err, "Error while creating transaction manager")
 	}
+	defer closeJournal()
 
-	gasLimit, err := strconv.Atoi(env["GAS_LIMIT"])
+	contractService, err := contract.New(client, logger, txm, cfg.HelloWorldAddress)
 	if err != nil {
-		logger.Fatalf("Error while parsing gas limit: %v\n", err)
+		return errors.Wrap(err, "Error while creating smart contract service")
 	}
 
-	gasPrice, err := strconv.Atoi(env["GAS_PRICE"])
+	eigenService, err := eigen.New(cfg.DelegationManagerAddress, client, logger, txm)
 	if err != nil {
-		logger.Fatalf("Error while parsing gas limit: %v\n", err)
+		return errors.Wrap(err, "Error while creating eigen smart contract service")
 	}
-	gasPriceInt := big.NewInt(int64(gasPrice))
 
-	contractService, err := contract.New(client, logger, uint64(gasLimit), gasPriceInt, env["HELLO_WORLD_ADDRESS"])
+	account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
 	if err != nil {
-		logger.Fatalf("Error while creating smart contract service: %v\n", err)
+		return errors.Wrap(err, "Error while creating signer")
 	}
 
-	eigenService, err := eigen.New(uint64(gasLimit), gasPriceInt, env["HOLESKY_DELEGATION_MANAGER_ADDRESS"], client, logger)
+	avs, err := eigenService.AVS(context.Background(), common.HexToAddress(cfg.HelloWorldAddress))
 	if err != nil {
-		logger.Fatalf("Error while creating eigen smart contract service: %v\n", err)
+		return errors.Wrap(err, "Error while looking up AVS contracts")
+	}
+	accounts, err := loadAccounts(cfg, account, logger)
+	if err != nil {
+		return errors.Wrap(err, "Error while loading accounts")
 	}
 
-	privateKey, err := crypto.HexToECDSA(env["WALLET_KEY"])
+	// Stop onboarding or taking new tasks on interrupt and let queued responses finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Tasks are processed only once every required onboarding step is complete,
+	// a restart resumes at the first missing step
+	onboardingConfig := cfg.OnboardingConfig()
+	onboardingConfig.Registration.SigningKey = accounts.SigningKeys.Latest()
+	if _, err := eigenService.Onboard(ctx, account, avs, onboardingConfig); err != nil {
+		if errors.Is(err, context.Canceled) {
+			logger.Printf("Stopped before onboarding was complete\n")
+			return nil
+		}
+		return errors.Wrap(err, "Error while onboarding operator")
+	}
+
+	scheme, err := contract.ParseSignatureScheme(cfg.SignatureScheme)
+	if err != nil {
+		return errors.Wrap(err, "Error while parsing signature scheme")
+	}
+	format, err := contract.ParseSignatureFormat(cfg.SignatureFormat)
 	if err != nil {
-		logger.Fatalf("Failed to load private key: %v\n", err)
+		return errors.Wrap(err, "Error while parsing signature format")
 	}
 
-	if err := eigenService.RegisterAsOperator(privateKey); err != nil {
-		logger.Fatalf("Error registering as operator: %v\n", err)
+	listenerConfig := contract.ListenerConfig{
+		Checkpoint:         checkpoint.New(cfg.CheckpointFile),
+		BackfillChunkSize:  cfg.BackfillChunkSize,
+		BackfillStartBlock: cfg.BackfillStartBlock,
+		Confirmations:      cfg.Confirmations,
+		Workers:            cfg.Workers,
+		QueueSize:          cfg.TaskQueueSize,
+		SignatureScheme:    scheme,
+		SignatureFormat:    format,
 	}
+	if cfg.WeightCheckBlocks > 0 {
+		listenerConfig.WeightGuard = contract.NewWeightGuard(accounts.Operator, cfg.WeightCheckBlocks)
+	}
+
+	stakeService, err := stake.New(client, logger, txm, avs.StakeRegistry)
+	if err != nil {
+		return errors.Wrap(err, "Error while creating stake registry service")
+	}
+	signingKey, err := stakeService.SigningKey(context.Background(), accounts.Operator)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting operator signing key")
+	}
+	if signingKey != (common.Address{}) && signingKey != accounts.SigningKeys.Latest() {
+		return errors.Errorf("Configured signing key %s is not the operator's signing key %s", accounts.SigningKeys.Latest().Hex(), signingKey.Hex())
+	}
+	head, err := client.BlockNumber(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "Error while getting block number")
+	}
+	// Replayed tasks are signed with the key registered at their block, which
+	// may be an older one than the configured key
+	from, err := listenerConfig.StartBlock(head)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting start block")
+	}
+	if err := loadSigningKeyHistory(ctx, cfg, stakeService, accounts, from, head, logger); err != nil {
+		return errors.Wrap(err, "Error while loading signing key history")
+	}
+
+	// SIGUSR1 does the same and then leaves the AVS
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var exiting atomic.Bool
+	exitSignal := make(chan os.Signal, 1)
+	signal.Notify(exitSignal, syscall.SIGUSR1)
+	go func() {
+		select {
+		case <-exitSignal:
+			logger.Printf("Exiting the AVS once queued responses are sent\n")
+			exiting.Store(true)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Switch to rotated signing keys at the block they take effect at
+	go stakeService.WatchSigningKeyUpdates(ctx, accounts.Operator, head+1, cfg.SigningKeyPollInterval, func(update *stakeregistry.ECDSAStakeRegistrySigningKeyUpdate) {
+		switchSigningKey(ctx, cfg, accounts.SigningKeys, update, logger)
+	})
 
-	if err := contractService.StartListeningForEvents(privateKey); err != nil {
-		logger.Fatalf("Error while listening for smart contract events: %v\n", err)
+	if err := contractService.StartListeningForEvents(ctx, accounts, listenerConfig); err != nil {
+		return errors.Wrap(err, "Error while listening for smart contract events")
 	}
 
+	if exiting.Load() {
+		if _, err := eigenService.Exit(context.Background(), account, avs, eigen.ExitConfig{}); err != nil {
+			return errors.Wrap(err, "Error while exiting the AVS")
+		}
+		logger.Printf("Operator %s left the AVS\n", account.Address().Hex())
+	}
+	return nil
+}
+
+// loadAccounts returns the signing key and submitters configured next to the operator key
+func loadAccounts(cfg *config.Config, operator signer.Signer, logger *log.Logger) (contract.Accounts, error) {
+	accounts := contract.SingleAccount(operator)
+
+	if signingConfig, ok := cfg.SigningSignerConfig(); ok {
+		signingKey, err := signer.New(context.Background(), signingConfig, logger)
+		if err != nil {
+			return accounts, err
+		}
+		accounts.SigningKeys = signer.NewKeyRing(signingKey)
+	}
+
+	if submitterConfigs := cfg.SubmitterSignerConfigs(); len(submitterConfigs) > 0 {
+		accounts.Submitters = nil
+		for _, submitterConfig := range submitterConfigs {
+			submitter, err := signer.New(context.Background(), submitterConfig, logger)
+			if err != nil {
+				return accounts, err
+			}
+			accounts.Submitters = append(accounts.Submitters, submitter)
+		}
+	}
+
+	logger.Printf("Operator %s signs tasks with %s and sends responses from %d submitters\n", accounts.Operator.Hex(), accounts.SigningKeys.Latest().Hex(), len(accounts.Submitters))
+	return accounts, nil
+}
+
+// loadSigningKeyHistory adds the signing keys in effect from block from to
+// head to the ring of accounts, which starts out with the configured key only
+func loadSigningKeyHistory(ctx context.Context, cfg *config.Config, stakeService *stake.Service, accounts contract.Accounts, from, head uint64, logger *log.Logger) error {
+	if from > head {
+		return nil
+	}
+	configured, err := accounts.SigningKeys.At(from)
+	if err != nil {
+		return err
+	}
+
+	key, err := stakeService.SigningKeyAt(ctx, accounts.Operator, from)
+	if err != nil {
+		return err
+	}
+	if key != (common.Address{}) && key != configured.Address() {
+		logger.Printf("Signing key at block %d is %s\n", from, key.Hex())
+		accounts.SigningKeys.Add(0, key, loadSigningKey(ctx, cfg, key, logger))
+	}
+
+	return stakeService.SigningKeyUpdates(ctx, accounts.Operator, from+1, head, func(update *stakeregistry.ECDSAStakeRegistrySigningKeyUpdate) {
+		if update.NewSigningKey == configured.Address() {
+			accounts.SigningKeys.Add(update.UpdateBlock.Uint64(), update.NewSigningKey, configured)
+			return
+		}
+		switchSigningKey(ctx, cfg, accounts.SigningKeys, update, logger)
+	})
+}
+
+// switchSigningKey loads the key an update rotated to and makes it sign tasks
+// from the update block on. When the key cannot be loaded those tasks are not
+// answered at all rather than signed with the old key.
+func switchSigningKey(ctx context.Context, cfg *config.Config, ring *signer.KeyRing, update *stakeregistry.ECDSAStakeRegistrySigningKeyUpdate, logger *log.Logger) {
+	block := update.UpdateBlock.Uint64()
+	logger.Printf("Signing key rotated from %s to %s at block %d\n", update.OldSigningKey.Hex(), update.NewSigningKey.Hex(), block)
+	ring.Add(block, update.NewSigningKey, loadSigningKey(ctx, cfg, update.NewSigningKey, logger))
+}
+
+// loadSigningKey returns the signer of a rotated signing key, or nil when it
+// cannot be loaded
+func loadSigningKey(ctx context.Context, cfg *config.Config, address common.Address, logger *log.Logger) signer.Signer {
+	signerConfig, err := cfg.RotatedSignerConfig(address)
+	if err != nil {
+		logger.Printf("Error while finding signing key %s: %v\n", address.Hex(), err)
+		return nil
+	}
+	key, err := signer.New(ctx, signerConfig, logger)
+	if err != nil {
+		logger.Printf("Error while loading signing key %s: %v\n", address.Hex(), err)
+		return nil
+	}
+	if key.Address() != address {
+		logger.Printf("Loaded key %s instead of signing key %s\n", key.Address().Hex(), address.Hex())
+		return nil
+	}
+	return key
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Updates the details and metadata URI of a registered operator from .env
+func main() {
+	logger := log.Default()
+
+	details := flag.Bool("details", true, "modify operator details when they differ from OPERATOR_* settings")
+	metadata := flag.Bool("metadata", true, "announce OPERATOR_METADATA_URI")
+	flag.Parse()
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+
+	client, err := setup.Dial(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger, delegationmanager.ContractDelegationManagerMetaData)
+	if err != nil {
+		logger.Fatalf("Error while creating transaction manager: %v\n", err)
+	}
+	defer closeJournal()
+
+	eigenService, err := eigen.New(cfg.DelegationManagerAddress, client, logger, txm)
+	if err != nil {
+		logger.Fatalf("Error while creating eigen smart contract service: %v\n", err)
+	}
+
+	account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
+	if err != nil {
+		logger.Fatalf("Error while creating signer: %v\n", err)
+	}
+
+	registered, err := eigenService.IsOperator(context.Background(), account.Address())
+	if err != nil {
+		logger.Fatalf("Error while checking operator registration: %v\n", err)
+	}
+	if !registered {
+		logger.Fatalf("%s is not registered as operator, run cmd/operator first\n", account.Address().Hex())
+	}
+
+	if *details {
+		modified, err := eigenService.ModifyOperatorDetails(context.Background(), account, cfg.OperatorDetails())
+		if err != nil {
+			logger.Fatalf("Error while modifying operator details: %v\n", err)
+		}
+		if !modified {
+			logger.Printf("Operator details are up to date\n")
+		}
+	}
+	if *metadata {
+		if err := eigenService.UpdateOperatorMetadataURI(context.Background(), account, cfg.OperatorDetails()); err != nil {
+			logger.Fatalf("Error while updating operator metadata URI: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/chain"
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/contract"
+	"github.com/patiee/avs-go-operator/keys"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/stake"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// Rotates the operator signing key in the stake registry. A running operator
+// switches to the new key at the block the update is mined in.
+func main() {
+	logger := log.Default()
+
+	addressFlag := flag.String("address", "", "existing key to rotate to, a new key is created in SIGNING_KEYSTORE_DIR when empty")
+	flag.Parse()
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+	// With the raw format the service manager only accepts responses signed by
+	// their sender, so every response after the rotation would revert
+	format, err := contract.ParseSignatureFormat(cfg.SignatureFormat)
+	if err != nil {
+		logger.Fatalf("Error while parsing signature format: %v\n", err)
+	}
+	if format != contract.StakeRegistryFormat {
+		logger.Fatalf("Rotating the signing key requires SIGNATURE_FORMAT=%s, it is %s\n", contract.StakeRegistryFormat, format)
+	}
+
+	// Stop waiting for confirmations on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := setup.Dial(ctx, cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger, stakeregistry.ECDSAStakeRegistryMetaData)
+	if err != nil {
+		logger.Fatalf("Error while creating transaction manager: %v\n", err)
+	}
+	defer closeJournal()
+
+	// updateOperatorSigningKey must be sent by the operator itself
+	operator, err := signer.New(ctx, cfg.SignerConfig(), logger)
+	if err != nil {
+		logger.Fatalf("Error while creating signer: %v\n", err)
+	}
+
+	contractService, err := contract.New(client, logger, txm, cfg.HelloWorldAddress)
+	if err != nil {
+		logger.Fatalf("Error while creating smart contract service: %v\n", err)
+	}
+	registryAddress, err := contractService.StakeRegistry(ctx)
+	if err != nil {
+		logger.Fatalf("Error while getting stake registry: %v\n", err)
+	}
+	stakeService, err := stake.New(client, logger, txm, registryAddress)
+	if err != nil {
+		logger.Fatalf("Error while creating stake registry service: %v\n", err)
+	}
+
+	newKey, err := newSigningKey(cfg, *addressFlag, logger)
+	if err != nil {
+		logger.Fatalf("Error while preparing new signing key: %v\n", err)
+	}
+
+	oldKey, err := stakeService.SigningKey(ctx, operator.Address())
+	if err != nil {
+		logger.Fatalf("Error while getting operator signing key: %v\n", err)
+	}
+	logger.Printf("Rotating signing key of %s from %s to %s\n", operator.Address().Hex(), oldKey.Hex(), newKey.Hex())
+
+	result, err := stakeService.UpdateSigningKey(ctx, operator, newKey)
+	if err != nil {
+		logger.Fatalf("Error while rotating signing key: %v\n", err)
+	}
+	if result.Status != txmgr.Mined {
+		logger.Fatalf("Signing key update %s is %s: %s\n", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+	effective := result.Receipt.BlockNumber.Uint64()
+
+	if err := waitForConfirmations(ctx, client, effective, cfg.Confirmations, cfg.ReceiptPollInterval); err != nil {
+		logger.Fatalf("Error while waiting for confirmations: %v\n", err)
+	}
+
+	current, err := stakeService.SigningKeyAt(ctx, operator.Address(), effective)
+	if err != nil {
+		logger.Fatalf("Error while checking signing key: %v\n", err)
+	}
+	if current != newKey {
+		logger.Fatalf("Signing key at block %d is %s, expected %s, the update may have been reorged out\n", effective, current.Hex(), newKey.Hex())
+	}
+
+	logger.Printf("Signing key %s is in effect from block %d, set it as SIGNING_KEYSTORE_FILE or SIGNING_REMOTE_ADDRESS before the next restart\n", newKey.Hex(), effective)
+}
+
+// newSigningKey checks the running operator will find the key of address, or
+// creates a new key in the signing keystore directory
+func newSigningKey(cfg *config.Config, address string, logger *log.Logger) (common.Address, error) {
+	if address != "" {
+		key := common.HexToAddress(address)
+		signerConfig, err := cfg.RotatedSignerConfig(key)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if signerConfig.Type == signer.RemoteType {
+			remote, err := signer.NewRemote(context.Background(), signerConfig.URL, signerConfig.Address)
+			if err != nil {
+				return common.Address{}, err
+			}
+			remote.Close()
+		}
+		return key, nil
+	}
+	if cfg.SignerType == signer.RemoteType {
+		return common.Address{}, errors.New("-address is required with a remote signer")
+	}
+
+	passphrase, err := keys.NewPassphrase(cfg.PassphraseFile)
+	if err != nil {
+		return common.Address{}, err
+	}
+	account, err := keys.Create(cfg.SigningKeystoreDir, passphrase)
+	if err != nil {
+		return common.Address{}, err
+	}
+	logger.Printf("Created signing key %s in %s\n", account.Address.Hex(), account.URL.Path)
+	return account.Address, nil
+}
+
+// waitForConfirmations polls every interval until block has confirmations
+// blocks on top of it, or ctx is done
+func waitForConfirmations(ctx context.Context, client *chain.Client, block, confirmations uint64, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		if head >= block+confirmations {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
-	"math/big"
-	"strconv"
+	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/joho/godotenv"
-	"github.com/patiee/avs-go-operator/contract"
 	"golang.org/x/exp/rand"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/contract"
+	"github.com/patiee/avs-go-operator/signer"
 )
 
 func generateRandomName() string {
@@ -30,46 +33,72 @@ func generateRandomName() string {
 func main() {
 	logger := log.Default()
 
+	index := flag.Int("index", -1, "index of the first wallet derived from MNEMONIC_FILE, HD_INDEX when not set")
+	count := flag.Int("count", 1, "number of wallets creating tasks, derived from consecutive indexes")
+	flag.Parse()
+
 	logger.Print("Starting go-operator")
 	defer logger.Print("go-operator exited")
 
-	env, err := godotenv.Read(".env")
+	cfg, err := config.Load(".env")
 	if err != nil {
-		logger.Fatalf("Error while reading .env file: %w", err)
+		logger.Fatalf("Error while loading config: %v\n", err)
 	}
-
-	client, err := ethclient.Dial(fmt.Sprintf("ws://%s", env["RPC_URL"]))
-	if err != nil {
-		logger.Fatalf("Error while connecting to Ethereum client: %w", err)
+	if *count < 1 {
+		logger.Fatalf("-count must be at least 1\n")
 	}
-
-	gasLimit, err := strconv.Atoi(env["GAS_LIMIT"])
-	if err != nil {
-		logger.Fatalf("Error while parsing gas limit: %w", err)
+	if *index >= 0 {
+		cfg.HDIndex = uint32(*index)
+	}
+	if *count > 1 && cfg.MnemonicFile == "" && cfg.Mnemonic == "" {
+		logger.Fatalf("-count requires MNEMONIC_FILE\n")
 	}
 
-	gasPrice, err := strconv.Atoi(env["GAS_PRICE"])
+	client, err := setup.Dial(context.Background(), cfg, logger)
 	if err != nil {
-		logger.Fatalf("Error while parsing gas limit: %v\n", err)
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
 	}
-	gasPriceInt := big.NewInt(int64(gasPrice))
+	defer client.Close()
 
-	contractService, err := contract.New(client, logger, uint64(gasLimit), gasPriceInt, env["HELLO_WORLD_ADDRESS"])
+	// Created tasks are not worth a journal
+	cfg.TxJournalFile = ""
+	txm, closeJournal, err := setup.TxManager(client, cfg, logger, helloworld.HelloWorldMetaData)
 	if err != nil {
-		logger.Fatalf("Error while creating smart contract service: %w", err)
+		logger.Fatalf("Error while creating transaction manager: %v\n", err)
 	}
+	defer closeJournal()
 
-	privateKey, err := crypto.HexToECDSA(env["WALLET_KEY"])
+	contractService, err := contract.New(client, logger, txm, cfg.HelloWorldAddress)
 	if err != nil {
-		logger.Fatalf("Failed to load private key: %w", err)
+		logger.Fatalf("Error while creating smart contract service: %v\n", err)
 	}
 
-	// Create a new task every 15 seconds
-	for {
-		if err := contractService.CreateNewTask(privateKey, generateRandomName()); err != nil {
-			logger.Fatalf("Failed to create a new task: %w", err)
+	accounts := make([]signer.Signer, 0, *count)
+	for i := 0; i < *count; i++ {
+		signerConfig := cfg.SignerConfig()
+		signerConfig.Keys.Index = cfg.HDIndex + uint32(i)
+		account, err := signer.New(context.Background(), signerConfig, logger)
+		if err != nil {
+			logger.Fatalf("Error while creating signer: %v\n", err)
 		}
+		logger.Printf("Creating tasks from %s\n", account.Address().Hex())
+		accounts = append(accounts, account)
+	}
 
-		time.Sleep(15 * time.Second)
+	// Every wallet creates a new task every 15 seconds
+	var wg sync.WaitGroup
+	for _, account := range accounts {
+		wg.Add(1)
+		go func(account signer.Signer) {
+			defer wg.Done()
+			for {
+				if err := contractService.CreateNewTask(account, generateRandomName()); err != nil {
+					logger.Fatalf("Failed to create a new task: %v\n", err)
+				}
+
+				time.Sleep(15 * time.Second)
+			}
+		}(account)
 	}
+	wg.Wait()
 }
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/patiee/avs-go-operator/cmd/internal/setup"
+	"github.com/patiee/avs-go-operator/config"
+	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Reports the restakeable strategies of the AVS, the shares delegated to the
+// operator in each and its weight in the stake registry
+func main() {
+	logger := log.Default()
+
+	index := flag.Int("index", -1, "index of the wallet derived from MNEMONIC_FILE, HD_INDEX when not set")
+	operator := flag.String("operator", "", "operator to report on, the configured operator key when empty")
+	format := flag.String("format", "table", "output format, table or json")
+	flag.Parse()
+
+	if *format != "table" && *format != "json" {
+		logger.Fatalf("Unknown format %q, use table or json\n", *format)
+	}
+	if *operator != "" && !common.IsHexAddress(*operator) {
+		logger.Fatalf("Invalid operator address %q\n", *operator)
+	}
+
+	cfg, err := config.Load(".env")
+	if err != nil {
+		logger.Fatalf("Error while loading config: %v\n", err)
+	}
+	if *index >= 0 {
+		if err := cfg.UseWallet(uint32(*index)); err != nil {
+			logger.Fatalf("Error while selecting wallet: %v\n", err)
+		}
+	}
+
+	client, err := setup.Dial(context.Background(), cfg, logger)
+	if err != nil {
+		logger.Fatalf("Error while connecting to Ethereum client: %v\n", err)
+	}
+	defer client.Close()
+
+	// The report only reads, so no transaction manager is needed
+	eigenService, err := eigen.New(cfg.DelegationManagerAddress, client, logger, nil)
+	if err != nil {
+		logger.Fatalf("Error while creating eigen smart contract service: %v\n", err)
+	}
+
+	address := common.HexToAddress(*operator)
+	if *operator == "" {
+		account, err := signer.New(context.Background(), cfg.SignerConfig(), logger)
+		if err != nil {
+			logger.Fatalf("Error while creating signer: %v\n", err)
+		}
+		address = account.Address()
+	}
+
+	avs, err := eigenService.AVS(context.Background(), common.HexToAddress(cfg.HelloWorldAddress))
+	if err != nil {
+		logger.Fatalf("Error while looking up AVS contracts: %v\n", err)
+	}
+	report, err := eigenService.StakeReport(context.Background(), address, avs)
+	if err != nil {
+		logger.Fatalf("Error while building stake report: %v\n", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			logger.Fatalf("Error while encoding stake report: %v\n", err)
+		}
+		return
+	}
+	if err := printTable(report); err != nil {
+		logger.Fatalf("Error while printing stake report: %v\n", err)
+	}
+}
+
+// printTable writes report as an aligned table
+func printTable(report eigen.StakeReport) error {
+	fmt.Printf("Operator %s at block %d\n\n", report.Operator.Hex(), report.Block)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STRATEGY\tTOKEN\tSYMBOL\tRESTAKED\tMULTIPLIER\tSHARES\tUNDERLYING")
+	for _, stake := range report.Strategies {
+		token := "-"
+		if stake.Token != (common.Address{}) {
+			token = stake.Token.Hex()
+		}
+		symbol := stake.Symbol
+		if symbol == "" {
+			symbol = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%s\n", stake.Strategy.Hex(), token, symbol, stake.Restaked, stake.Multiplier, stake.Shares, formatUnits(stake.Underlying, stake.Decimals))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nWeight %s, minimum %s, threshold %s, total %s, has minimum weight: %t\n", report.Weight, report.MinimumWeight, report.ThresholdWeight, report.TotalWeight, report.HasMinimumWeight)
+	return nil
+}
+
+// formatUnits formats amount as a decimal with the given number of decimals
+func formatUnits(amount *big.Int, decimals uint8) string {
+	if decimals == 0 {
+		return amount.String()
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(new(big.Int).Abs(amount), unit, new(big.Int))
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	fraction := strings.TrimRight(fmt.Sprintf("%0*s", decimals, frac.String()), "0")
+	if fraction == "" {
+		return sign + whole.String()
+	}
+	return sign + whole.String() + "." + fraction
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{amount: "0", decimals: 18, want: "0"},
+		{amount: "1000000000000000000", decimals: 18, want: "1"},
+		{amount: "1500000000000000000", decimals: 18, want: "1.5"},
+		{amount: "1", decimals: 18, want: "0.000000000000000001"},
+		{amount: "123456789", decimals: 6, want: "123.456789"},
+		{amount: "-2500000", decimals: 6, want: "-2.5"},
+		{amount: "-1", decimals: 2, want: "-0.01"},
+		{amount: "42", decimals: 0, want: "42"},
+		{amount: "340282366920938463463374607431768211456", decimals: 18, want: "340282366920938463463.374607431768211456"},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := formatUnits(amount, tt.decimals); got != tt.want {
+			t.Fatalf("formatUnits(%s, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+}
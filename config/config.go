@@ -0,0 +1,660 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/contract"
+	"github.com/patiee/avs-go-operator/eigen"
+	"github.com/patiee/avs-go-operator/fees"
+	"github.com/patiee/avs-go-operator/gas"
+	"github.com/patiee/avs-go-operator/keys"
+	"github.com/patiee/avs-go-operator/metadata"
+	"github.com/patiee/avs-go-operator/onboarding"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// Config holds values read from the .env file
+type Config struct {
+	RPCURL              string
+	WalletKey           string
+	KeystoreFile        string
+	PassphraseFile      string
+	SignerType          string
+	RemoteSignerURL     string
+	RemoteSignerAddress string
+	MnemonicFile        string
+	Mnemonic            string
+	HDPath              accounts.DerivationPath
+	// HDIndex selects the wallet derived from the mnemonic, commands override it with -index
+	HDIndex uint32
+
+	SigningKeystoreFile      string
+	SigningWalletKey         string
+	SigningRemoteAddress     string
+	SigningKeystoreDir       string
+	SigningKeyPollInterval   time.Duration
+	SubmitterKeystoreFiles   []string
+	SubmitterWalletKeys      []string
+	SubmitterRemoteAddresses []string
+	GasLimit                 uint64
+	GasMultiplier            float64
+	GasCeilings              map[string]uint64
+	GasPrice                 *big.Int
+	FeeStrategy              string
+	PriorityFee              *big.Int
+	BaseFeeMultiplier        int64
+	HelloWorldAddress        string
+	DelegationManagerAddress string
+	// Operator details registered with the delegation manager
+	OperatorEarningsReceiver         common.Address
+	OperatorDelegationApprover       common.Address
+	OperatorStakerOptOutWindowBlocks uint32
+	OperatorMetadataURI              string
+	OperatorMetadataHash             common.Hash
+	MetadataAllowLocal               bool
+	// Operator metadata published at the metadata URI
+	OperatorName        string
+	OperatorWebsite     string
+	OperatorDescription string
+	OperatorLogo        string
+	OperatorTwitter     string
+	// AVSRegistrationExpiry is how long the AVS registration signature stays valid
+	AVSRegistrationExpiry time.Duration
+	// Onboarding progress file, steps that do not block task processing and retries
+	OnboardingFile          string
+	OnboardingOptionalSteps []onboarding.Step
+	OnboardingPollInterval  time.Duration
+	OnboardingMaxAttempts   int
+
+	CheckpointFile     string
+	BackfillChunkSize  uint64
+	BackfillStartBlock uint64
+	Confirmations      uint64
+	Workers            int
+	TaskQueueSize      int
+	SignatureScheme    string
+	SignatureFormat    string
+	WeightCheckBlocks  uint64
+
+	ReceiptTimeout      time.Duration
+	ReceiptPollInterval time.Duration
+	TxMaxAttempts       int
+	TxRetryBackoff      time.Duration
+	TxRetryReverted     bool
+	TxJournalFile       string
+	TxReplaceAfter      time.Duration
+	TxBumpPercent       int64
+	TxMaxGasPrice       *big.Int
+
+	ReconnectMinBackoff  time.Duration
+	ReconnectMaxBackoff  time.Duration
+	ReconnectMaxAttempts int
+}
+
+const (
+	defaultGasMultiplier = 1.2
+
+	defaultSigningKeystoreDir     = "keystore"
+	defaultSigningKeyPollInterval = 12 * time.Second
+
+	defaultAVSRegistrationExpiry = time.Hour
+
+	defaultOnboardingFile         = "onboarding.json"
+	defaultOnboardingPollInterval = 30 * time.Second
+	defaultOnboardingMaxAttempts  = 3
+
+	defaultFeeStrategy       = fees.EIP1559
+	defaultBaseFeeMultiplier = 2
+
+	defaultCheckpointFile    = "checkpoint.json"
+	defaultBackfillChunkSize = 1000
+	defaultConfirmations     = 2
+	defaultWorkers           = 4
+	defaultTaskQueueSize     = 64
+	defaultWeightCheckBlocks = 1
+	defaultSignatureScheme   = "eip191"
+	defaultSignatureFormat   = "raw"
+
+	defaultReceiptTimeout      = 2 * time.Minute
+	defaultReceiptPollInterval = 2 * time.Second
+	defaultTxMaxAttempts       = 3
+	defaultTxRetryBackoff      = 5 * time.Second
+	defaultTxJournalFile       = "transactions.jsonl"
+	defaultTxReplaceAfter      = 30 * time.Second
+	defaultTxBumpPercent       = 15
+
+	defaultReconnectMinBackoff = time.Second
+	defaultReconnectMaxBackoff = time.Minute
+)
+
+// Load reads config from .env file at given path
+func Load(path string) (*Config, error) {
+	env, err := godotenv.Read(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while reading .env file")
+	}
+
+	gasLimit, err := uintOr(env, "GAS_LIMIT", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, err := bigIntOr(env, "GAS_PRICE")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{
+		RPCURL:                   env["RPC_URL"],
+		WalletKey:                env["WALLET_KEY"],
+		KeystoreFile:             env["KEYSTORE_FILE"],
+		PassphraseFile:           env["KEYSTORE_PASSPHRASE_FILE"],
+		SignerType:               stringOr(env, "SIGNER_TYPE", signer.LocalType),
+		RemoteSignerURL:          env["REMOTE_SIGNER_URL"],
+		RemoteSignerAddress:      env["REMOTE_SIGNER_ADDRESS"],
+		MnemonicFile:             env["MNEMONIC_FILE"],
+		Mnemonic:                 env["MNEMONIC"],
+		SigningKeystoreFile:      env["SIGNING_KEYSTORE_FILE"],
+		SigningWalletKey:         env["SIGNING_WALLET_KEY"],
+		SigningRemoteAddress:     env["SIGNING_REMOTE_ADDRESS"],
+		SigningKeystoreDir:       stringOr(env, "SIGNING_KEYSTORE_DIR", defaultSigningKeystoreDir),
+		SubmitterKeystoreFiles:   list(env, "SUBMITTER_KEYSTORE_FILES"),
+		SubmitterWalletKeys:      list(env, "SUBMITTER_WALLET_KEYS"),
+		SubmitterRemoteAddresses: list(env, "SUBMITTER_REMOTE_ADDRESSES"),
+		SignatureFormat:          stringOr(env, "SIGNATURE_FORMAT", defaultSignatureFormat),
+		GasLimit:                 gasLimit,
+		GasPrice:                 gasPrice,
+		FeeStrategy:              stringOr(env, "FEE_STRATEGY", defaultFeeStrategy),
+		HelloWorldAddress:        env["HELLO_WORLD_ADDRESS"],
+		DelegationManagerAddress: env["HOLESKY_DELEGATION_MANAGER_ADDRESS"],
+		OperatorMetadataURI:      env["OPERATOR_METADATA_URI"],
+		OperatorName:             env["OPERATOR_NAME"],
+		OperatorWebsite:          env["OPERATOR_WEBSITE"],
+		OperatorDescription:      env["OPERATOR_DESCRIPTION"],
+		OperatorLogo:             env["OPERATOR_LOGO"],
+		OperatorTwitter:          env["OPERATOR_TWITTER"],
+		OnboardingFile:           stringOr(env, "ONBOARDING_FILE", defaultOnboardingFile),
+		CheckpointFile:           stringOr(env, "CHECKPOINT_FILE", defaultCheckpointFile),
+		SignatureScheme:          stringOr(env, "SIGNATURE_SCHEME", defaultSignatureScheme),
+	}
+
+	if cfg.HDPath, err = accounts.ParseDerivationPath(stringOr(env, "HD_PATH", keys.DefaultHDPath.String())); err != nil {
+		return nil, errors.Wrap(err, "Error while parsing HD_PATH")
+	}
+	hdIndex, err := uintOr(env, "HD_INDEX", 0)
+	if err != nil {
+		return nil, err
+	}
+	if hdIndex >= 1<<31 {
+		return nil, errors.New("HD_INDEX must be lower than 2^31")
+	}
+	cfg.HDIndex = uint32(hdIndex)
+
+	if cfg.OperatorEarningsReceiver, err = addressOr(env, "OPERATOR_EARNINGS_RECEIVER"); err != nil {
+		return nil, err
+	}
+	if cfg.OperatorDelegationApprover, err = addressOr(env, "OPERATOR_DELEGATION_APPROVER"); err != nil {
+		return nil, err
+	}
+	if hash := env["OPERATOR_METADATA_HASH"]; hash != "" {
+		data, err := hexutil.Decode(hash)
+		if err != nil || len(data) != common.HashLength {
+			return nil, errors.New("OPERATOR_METADATA_HASH must be a 32 byte hex hash")
+		}
+		cfg.OperatorMetadataHash = common.BytesToHash(data)
+	}
+	if cfg.MetadataAllowLocal, err = boolOr(env, "METADATA_ALLOW_LOCAL", false); err != nil {
+		return nil, err
+	}
+	optOutWindow, err := uintOr(env, "OPERATOR_STAKER_OPT_OUT_WINDOW_BLOCKS", 0)
+	if err != nil {
+		return nil, err
+	}
+	if optOutWindow > eigen.MaxStakerOptOutWindowBlocks {
+		return nil, errors.Errorf("OPERATOR_STAKER_OPT_OUT_WINDOW_BLOCKS must not exceed %d", eigen.MaxStakerOptOutWindowBlocks)
+	}
+	cfg.OperatorStakerOptOutWindowBlocks = uint32(optOutWindow)
+
+	if cfg.AVSRegistrationExpiry, err = durationOr(env, "AVS_REGISTRATION_EXPIRY", defaultAVSRegistrationExpiry); err != nil {
+		return nil, err
+	}
+	if cfg.OnboardingOptionalSteps, err = onboarding.ParseSteps(env["ONBOARDING_OPTIONAL_STEPS"]); err != nil {
+		return nil, errors.Wrap(err, "Error while parsing ONBOARDING_OPTIONAL_STEPS")
+	}
+	for _, step := range cfg.OnboardingOptionalSteps {
+		if !step.Skippable() {
+			return nil, errors.Errorf("ONBOARDING_OPTIONAL_STEPS can only contain %s and %s", onboarding.Deposit, onboarding.MinimumWeight)
+		}
+	}
+	if cfg.OnboardingPollInterval, err = durationOr(env, "ONBOARDING_POLL_INTERVAL", defaultOnboardingPollInterval); err != nil {
+		return nil, err
+	}
+	if cfg.OnboardingMaxAttempts, err = positiveIntOr(env, "ONBOARDING_MAX_ATTEMPTS", defaultOnboardingMaxAttempts); err != nil {
+		return nil, err
+	}
+
+	if cfg.SigningKeyPollInterval, err = durationOr(env, "SIGNING_KEY_POLL_INTERVAL", defaultSigningKeyPollInterval); err != nil {
+		return nil, err
+	}
+	format, err := contract.ParseSignatureFormat(cfg.SignatureFormat)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while parsing SIGNATURE_FORMAT")
+	}
+	// The service manager only accepts raw signatures of the sender of the response
+	if _, separate := cfg.SigningSignerConfig(); format == contract.RawFormat && (separate || len(cfg.SubmitterSignerConfigs()) > 0) {
+		return nil, errors.New("SIGNATURE_FORMAT=raw requires the operator key to sign and send responses, use SIGNATURE_FORMAT=stake-registry with signing or submitter keys")
+	}
+
+	if cfg.GasMultiplier, err = floatOr(env, "GAS_MULTIPLIER", defaultGasMultiplier); err != nil {
+		return nil, err
+	}
+	if cfg.GasMultiplier < 1 {
+		return nil, errors.New("GAS_MULTIPLIER must be at least 1")
+	}
+	if cfg.GasCeilings, err = ceilings(env, "GAS_CEILINGS"); err != nil {
+		return nil, err
+	}
+
+	if cfg.PriorityFee, err = bigIntOr(env, "PRIORITY_FEE"); err != nil {
+		return nil, err
+	}
+	multiplier, err := uintOr(env, "BASE_FEE_MULTIPLIER", defaultBaseFeeMultiplier)
+	if err != nil {
+		return nil, err
+	}
+	cfg.BaseFeeMultiplier = int64(multiplier)
+
+	if cfg.BackfillChunkSize, err = uintOr(env, "BACKFILL_CHUNK_SIZE", defaultBackfillChunkSize); err != nil {
+		return nil, err
+	}
+	if cfg.BackfillChunkSize == 0 {
+		return nil, errors.New("BACKFILL_CHUNK_SIZE must be greater than 0")
+	}
+	if cfg.BackfillStartBlock, err = uintOr(env, "BACKFILL_START_BLOCK", 0); err != nil {
+		return nil, err
+	}
+	if cfg.Confirmations, err = uintOr(env, "CONFIRMATIONS", defaultConfirmations); err != nil {
+		return nil, err
+	}
+	if cfg.Workers, err = positiveIntOr(env, "WORKERS", defaultWorkers); err != nil {
+		return nil, err
+	}
+	if cfg.TaskQueueSize, err = positiveIntOr(env, "TASK_QUEUE_SIZE", defaultTaskQueueSize); err != nil {
+		return nil, err
+	}
+	if cfg.WeightCheckBlocks, err = uintOr(env, "WEIGHT_CHECK_BLOCKS", defaultWeightCheckBlocks); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReceiptTimeout, err = durationOr(env, "RECEIPT_TIMEOUT", defaultReceiptTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.ReceiptPollInterval, err = durationOr(env, "RECEIPT_POLL_INTERVAL", defaultReceiptPollInterval); err != nil {
+		return nil, err
+	}
+	if cfg.TxMaxAttempts, err = positiveIntOr(env, "TX_MAX_ATTEMPTS", defaultTxMaxAttempts); err != nil {
+		return nil, err
+	}
+	if cfg.TxRetryBackoff, err = durationOr(env, "TX_RETRY_BACKOFF", defaultTxRetryBackoff); err != nil {
+		return nil, err
+	}
+	if cfg.TxRetryReverted, err = boolOr(env, "TX_RETRY_REVERTED", false); err != nil {
+		return nil, err
+	}
+	cfg.TxJournalFile = stringOr(env, "TX_JOURNAL_FILE", defaultTxJournalFile)
+	if cfg.TxReplaceAfter, err = durationOr(env, "TX_REPLACE_AFTER", defaultTxReplaceAfter); err != nil {
+		return nil, err
+	}
+	bumpPercent, err := uintOr(env, "TX_BUMP_PERCENT", defaultTxBumpPercent)
+	if err != nil {
+		return nil, err
+	}
+	// Nodes reject replacements that raise the fee by less than 10%
+	if bumpPercent < 10 {
+		return nil, errors.New("TX_BUMP_PERCENT must be at least 10")
+	}
+	cfg.TxBumpPercent = int64(bumpPercent)
+	if cfg.TxMaxGasPrice, err = bigIntOr(env, "TX_MAX_GAS_PRICE"); err != nil {
+		return nil, err
+	}
+	if cfg.TxMaxGasPrice != nil && cfg.TxMaxGasPrice.Sign() == 0 {
+		cfg.TxMaxGasPrice = nil
+	}
+
+	if cfg.ReconnectMinBackoff, err = durationOr(env, "RECONNECT_MIN_BACKOFF", defaultReconnectMinBackoff); err != nil {
+		return nil, err
+	}
+	if cfg.ReconnectMaxBackoff, err = durationOr(env, "RECONNECT_MAX_BACKOFF", defaultReconnectMaxBackoff); err != nil {
+		return nil, err
+	}
+	if cfg.ReconnectMaxBackoff < cfg.ReconnectMinBackoff {
+		return nil, errors.New("RECONNECT_MAX_BACKOFF must not be lower than RECONNECT_MIN_BACKOFF")
+	}
+	maxAttempts, err := uintOr(env, "RECONNECT_MAX_ATTEMPTS", 0)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ReconnectMaxAttempts = int(maxAttempts)
+
+	return cfg, nil
+}
+
+// TxManagerConfig returns the transaction manager settings
+func (c *Config) TxManagerConfig() txmgr.Config {
+	return txmgr.Config{
+		ReceiptTimeout: c.ReceiptTimeout,
+		PollInterval:   c.ReceiptPollInterval,
+		Retry: txmgr.RetryPolicy{
+			MaxAttempts:   c.TxMaxAttempts,
+			Backoff:       c.TxRetryBackoff,
+			RetryReverted: c.TxRetryReverted,
+		},
+		Replace: txmgr.ReplacePolicy{
+			After:       c.TxReplaceAfter,
+			BumpPercent: c.TxBumpPercent,
+			MaxGasPrice: c.TxMaxGasPrice,
+		},
+	}
+}
+
+// KeyConfig returns where the operator key is loaded from
+func (c *Config) KeyConfig() keys.Config {
+	return keys.Config{
+		KeystoreFile:   c.KeystoreFile,
+		PassphraseFile: c.PassphraseFile,
+		HexKey:         c.WalletKey,
+		MnemonicFile:   c.MnemonicFile,
+		Mnemonic:       c.Mnemonic,
+		HDPath:         c.HDPath,
+		Index:          c.HDIndex,
+	}
+}
+
+// UseWallet selects the wallet at index of the mnemonic and gives it its own
+// checkpoint, journal and onboarding files, so several operators can run from one directory
+func (c *Config) UseWallet(index uint32) error {
+	if c.MnemonicFile == "" && c.Mnemonic == "" {
+		return errors.New("Selecting a wallet by index requires MNEMONIC_FILE")
+	}
+	c.HDIndex = index
+	c.CheckpointFile = indexed(c.CheckpointFile, index)
+	c.TxJournalFile = indexed(c.TxJournalFile, index)
+	c.OnboardingFile = indexed(c.OnboardingFile, index)
+	return nil
+}
+
+// SignerConfig returns the signer settings
+func (c *Config) SignerConfig() signer.Config {
+	return signer.Config{
+		Type:    c.SignerType,
+		Keys:    c.KeyConfig(),
+		URL:     c.RemoteSignerURL,
+		Address: c.RemoteSignerAddress,
+	}
+}
+
+// SigningSignerConfig returns the settings of the task signing key, it
+// reports false when the operator key signs tasks too
+func (c *Config) SigningSignerConfig() (signer.Config, bool) {
+	cfg := c.SignerConfig()
+	switch {
+	case c.SignerType == signer.RemoteType && c.SigningRemoteAddress != "":
+		cfg.Address = c.SigningRemoteAddress
+	case c.SignerType != signer.RemoteType && (c.SigningKeystoreFile != "" || c.SigningWalletKey != ""):
+		cfg.Keys = keys.Config{KeystoreFile: c.SigningKeystoreFile, PassphraseFile: c.PassphraseFile, HexKey: c.SigningWalletKey}
+	default:
+		return signer.Config{}, false
+	}
+	return cfg, true
+}
+
+// RotatedSignerConfig returns the settings of a signing key rotated to
+// address, which is looked up in SIGNING_KEYSTORE_DIR or on the remote signer
+func (c *Config) RotatedSignerConfig(address common.Address) (signer.Config, error) {
+	cfg := c.SignerConfig()
+	if c.SignerType == signer.RemoteType {
+		cfg.Address = address.Hex()
+		return cfg, nil
+	}
+
+	path, err := keys.Find(c.SigningKeystoreDir, address)
+	if err != nil {
+		return signer.Config{}, err
+	}
+	cfg.Keys = keys.Config{KeystoreFile: path, PassphraseFile: c.PassphraseFile}
+	return cfg, nil
+}
+
+// SubmitterSignerConfigs returns the settings of the keys paying for response
+// transactions, none means the operator key sends them
+func (c *Config) SubmitterSignerConfigs() []signer.Config {
+	var configs []signer.Config
+	if c.SignerType == signer.RemoteType {
+		for _, address := range c.SubmitterRemoteAddresses {
+			cfg := c.SignerConfig()
+			cfg.Address = address
+			configs = append(configs, cfg)
+		}
+		return configs
+	}
+
+	for _, file := range c.SubmitterKeystoreFiles {
+		cfg := c.SignerConfig()
+		cfg.Keys = keys.Config{KeystoreFile: file, PassphraseFile: c.PassphraseFile}
+		configs = append(configs, cfg)
+	}
+	for _, key := range c.SubmitterWalletKeys {
+		cfg := c.SignerConfig()
+		cfg.Keys = keys.Config{HexKey: key}
+		configs = append(configs, cfg)
+	}
+	return configs
+}
+
+// RegistrationConfig returns the operator registration settings
+func (c *Config) RegistrationConfig() eigen.RegistrationConfig {
+	return eigen.RegistrationConfig{
+		Details:   c.OperatorDetails(),
+		AVSExpiry: c.AVSRegistrationExpiry,
+	}
+}
+
+// OnboardingConfig returns the onboarding settings, the signing key is left for the caller
+func (c *Config) OnboardingConfig() eigen.OnboardingConfig {
+	return eigen.OnboardingConfig{
+		Registration: c.RegistrationConfig(),
+		Store:        onboarding.New(c.OnboardingFile),
+		Optional:     c.OnboardingOptionalSteps,
+		PollInterval: c.OnboardingPollInterval,
+		MaxAttempts:  c.OnboardingMaxAttempts,
+	}
+}
+
+// OperatorDetails returns the details the operator registers with
+func (c *Config) OperatorDetails() eigen.OperatorDetails {
+	return eigen.OperatorDetails{
+		EarningsReceiver:         c.OperatorEarningsReceiver,
+		DelegationApprover:       c.OperatorDelegationApprover,
+		StakerOptOutWindowBlocks: c.OperatorStakerOptOutWindowBlocks,
+		MetadataURI:              c.OperatorMetadataURI,
+		MetadataHash:             c.OperatorMetadataHash,
+		AllowLocalMetadata:       c.MetadataAllowLocal,
+	}
+}
+
+// Metadata returns the operator metadata document built from config
+func (c *Config) Metadata() metadata.Metadata {
+	return metadata.Metadata{
+		Name:        c.OperatorName,
+		Website:     c.OperatorWebsite,
+		Description: c.OperatorDescription,
+		Logo:        c.OperatorLogo,
+		Twitter:     c.OperatorTwitter,
+	}
+}
+
+// GasConfig returns the gas estimation settings
+func (c *Config) GasConfig() gas.Config {
+	return gas.Config{
+		Multiplier: c.GasMultiplier,
+		Ceilings:   c.GasCeilings,
+		Fallback:   c.GasLimit,
+	}
+}
+
+// FeeConfig returns the fee strategy settings
+func (c *Config) FeeConfig() fees.Config {
+	return fees.Config{
+		Strategy:          c.FeeStrategy,
+		GasPrice:          c.GasPrice,
+		MaxFee:            c.TxMaxGasPrice,
+		PriorityFee:       c.PriorityFee,
+		BaseFeeMultiplier: c.BaseFeeMultiplier,
+	}
+}
+
+// WebsocketURL returns the RPC endpoint as a websocket url
+func (c *Config) WebsocketURL() string {
+	return fmt.Sprintf("ws://%s", c.RPCURL)
+}
+
+// indexed inserts index before the extension of path
+func indexed(path string, index uint32) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), index, ext)
+}
+
+func stringOr(env map[string]string, key, def string) string {
+	if v, ok := env[key]; ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func uintOr(env map[string]string, key string, def uint64) (uint64, error) {
+	v, ok := env[key]
+	if !ok || v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error while parsing %s", key)
+	}
+	return n, nil
+}
+
+func positiveIntOr(env map[string]string, key string, def int) (int, error) {
+	n, err := uintOr(env, key, uint64(def))
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, errors.Errorf("%s must be greater than 0", key)
+	}
+	return int(n), nil
+}
+
+// bigIntOr parses an optional decimal amount in wei, it returns nil when unset
+func bigIntOr(env map[string]string, key string) (*big.Int, error) {
+	v, ok := env[key]
+	if !ok || v == "" {
+		return nil, nil
+	}
+	n, ok := new(big.Int).SetString(v, 10)
+	if !ok || n.Sign() < 0 {
+		return nil, errors.Errorf("Error while parsing %s %q", key, v)
+	}
+	return n, nil
+}
+
+// list parses a comma separated list
+func list(env map[string]string, key string) []string {
+	var values []string
+	for _, v := range strings.Split(env[key], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// addressOr returns the address at key, the zero address when it is empty
+func addressOr(env map[string]string, key string) (common.Address, error) {
+	value, ok := env[key]
+	if !ok || value == "" {
+		return common.Address{}, nil
+	}
+	if !common.IsHexAddress(value) {
+		return common.Address{}, errors.Errorf("%s must be an address", key)
+	}
+	return common.HexToAddress(value), nil
+}
+
+func floatOr(env map[string]string, key string, def float64) (float64, error) {
+	v, ok := env[key]
+	if !ok || v == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error while parsing %s", key)
+	}
+	return f, nil
+}
+
+// ceilings parses a comma separated list of method=limit pairs
+func ceilings(env map[string]string, key string) (map[string]uint64, error) {
+	result := make(map[string]uint64)
+	for _, pair := range strings.Split(env[key], ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		method, limit, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, errors.Errorf("Error while parsing %s, expected method=limit, got %q", key, pair)
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(limit), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while parsing %s limit of %s", key, method)
+		}
+		result[strings.TrimSpace(method)] = n
+	}
+	return result, nil
+}
+
+func boolOr(env map[string]string, key string, def bool) (bool, error) {
+	v, ok := env[key]
+	if !ok || v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.Wrapf(err, "Error while parsing %s", key)
+	}
+	return b, nil
+}
+
+func durationOr(env map[string]string, key string, def time.Duration) (time.Duration, error) {
+	v, ok := env[key]
+	if !ok || v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error while parsing %s", key)
+	}
+	return d, nil
+}
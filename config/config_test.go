@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func load(t *testing.T, env string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(env), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return Load(path)
+}
+
+func TestLoadSignatureFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		wantErr bool
+	}{
+		{name: "raw with the operator key", env: "SIGNATURE_FORMAT=raw\n"},
+		{name: "default with a signing key", env: "SIGNING_WALLET_KEY=0x01\n", wantErr: true},
+		{name: "raw with submitters", env: "SIGNATURE_FORMAT=raw\nSUBMITTER_WALLET_KEYS=0x01,0x02\n", wantErr: true},
+		{name: "stake registry with submitters", env: "SIGNATURE_FORMAT=stake-registry\nSIGNING_WALLET_KEY=0x01\nSUBMITTER_WALLET_KEYS=0x02\n"},
+		{name: "unknown format", env: "SIGNATURE_FORMAT=compact\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.env)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Accounts are the keys the operator answers tasks with. Keeping the signing
+// key apart from the keys paying for gas lets gas wallets be rotated freely.
+type Accounts struct {
+	// Operator is the address registered as the operator, responses are attributed to it
+	Operator common.Address
+	// SigningKeys sign task responses, they are the operator's signing keys in the stake registry
+	SigningKeys *signer.KeyRing
+	// Submitters send response transactions and pay for their gas, they are used in turn
+	Submitters []signer.Signer
+}
+
+// SingleAccount returns Accounts using account for every role
+func SingleAccount(account signer.Signer) Accounts {
+	return Accounts{
+		Operator:    account.Address(),
+		SigningKeys: signer.NewKeyRing(account),
+		Submitters:  []signer.Signer{account},
+	}
+}
+
+func (a Accounts) validate() error {
+	if a.SigningKeys == nil {
+		return errors.New("No signing key configured")
+	}
+	if len(a.Submitters) == 0 {
+		return errors.New("No submitter configured")
+	}
+	return nil
+}
+
+// responders returns every address a response to a task may have been sent from
+func (a Accounts) responders() []common.Address {
+	addresses := []common.Address{a.Operator}
+	for _, submitter := range a.Submitters {
+		if !contains(addresses, submitter.Address()) {
+			addresses = append(addresses, submitter.Address())
+		}
+	}
+	return addresses
+}
+
+// rotation hands out submitters round robin so gas is spread over all of them
+type rotation struct {
+	submitters []signer.Signer
+	next       atomic.Uint64
+}
+
+func (r *rotation) pick() signer.Signer {
+	n := r.next.Add(1) - 1
+	return r.submitters[n%uint64(len(r.submitters))]
+}
+
+func contains(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
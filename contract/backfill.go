@@ -0,0 +1,149 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/checkpoint"
+)
+
+// taskBufferSize is how many live tasks can queue up while replay is running
+const taskBufferSize = 1024
+
+// ListenerConfig configures StartListeningForEvents
+type ListenerConfig struct {
+	// Checkpoint persists the last fully processed block
+	Checkpoint *checkpoint.Store
+	// BackfillChunkSize is the maximum block range of a single log filter call
+	BackfillChunkSize uint64
+	// BackfillStartBlock is where replay starts when there is no checkpoint yet,
+	// zero means only tasks created from now on are answered
+	BackfillStartBlock uint64
+	// Confirmations is the number of blocks built on top of a task's block
+	// before the task is answered, zero answers tasks as soon as they arrive
+	Confirmations uint64
+	// Workers is the number of tasks answered concurrently
+	Workers int
+	// QueueSize is the number of tasks waiting for a worker before ingestion blocks
+	QueueSize int
+	// SignatureScheme selects the digest responses are signed over, EIP191 when empty
+	SignatureScheme SignatureScheme
+	// SignatureFormat selects how signatures are encoded, RawFormat when empty
+	SignatureFormat SignatureFormat
+	// WeightGuard pauses responses while the operator is below the minimum
+	// weight, it is checked at startup and on new blocks. Nil never pauses.
+	WeightGuard *WeightGuard
+}
+
+// StartBlock returns the first block to replay, which is past head when there is nothing to replay
+func (cfg ListenerConfig) StartBlock(head uint64) (uint64, error) {
+	last, ok, err := cfg.Checkpoint.Load()
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return last + 1, nil
+	}
+	if cfg.BackfillStartBlock > 0 {
+		return cfg.BackfillStartBlock, nil
+	}
+	return head + 1, nil
+}
+
+// replay queues tasks created in blocks [from, to] in chunks, advancing the checkpoint after each chunk
+func (s *Service) replay(ctx context.Context, pool *workerPool, progress *progress, chunkSize, from, to uint64) error {
+	if from > to {
+		return progress.queued(to)
+	}
+
+	s.logger.Printf("Replaying tasks from block %d to %d\n", from, to)
+	submit := func(task *helloworld.HelloWorldNewTaskCreated) error {
+		return pool.submit(ctx, task)
+	}
+	if err := s.filterTasks(ctx, from, to, chunkSize, submit, progress.queued); err != nil {
+		return err
+	}
+
+	s.logger.Printf("Replay queued up to block %d\n", to)
+	return nil
+}
+
+// filterTasks calls fn for every task created in blocks [from, to] and done after each chunk of blocks
+func (s *Service) filterTasks(ctx context.Context, from, to, chunkSize uint64, fn func(*helloworld.HelloWorldNewTaskCreated) error, done func(end uint64) error) error {
+	for start := from; start <= to; start += chunkSize {
+		end := min(start+chunkSize-1, to)
+
+		it, err := s.helloWorld.FilterNewTaskCreated(&bind.FilterOpts{Start: start, End: &end, Context: ctx}, nil)
+		if err != nil {
+			return errors.Wrapf(err, "Error while filtering tasks in blocks %d-%d", start, end)
+		}
+
+		for it.Next() {
+			if err := fn(it.Event); err != nil {
+				it.Close()
+				return err
+			}
+		}
+		if err := it.Error(); err != nil {
+			it.Close()
+			return errors.Wrapf(err, "Error while iterating tasks in blocks %d-%d", start, end)
+		}
+		it.Close()
+
+		if done != nil {
+			if err := done(end); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// progress saves the checkpoint, never moving it past a task that is not answered yet
+type progress struct {
+	store *checkpoint.Store
+	pool  *workerPool
+	saved uint64
+	ok    bool
+	// handed is the last block whose tasks were all handed to the pool
+	handed   uint64
+	handedOK bool
+}
+
+// queued records that every task up to block was handed to the pool and saves the checkpoint
+func (p *progress) queued(block uint64) error {
+	if !p.handedOK || block > p.handed {
+		p.handed, p.handedOK = block, true
+	}
+	return p.save(block)
+}
+
+// flush saves the checkpoint up to the last block whose tasks were all handed
+// to the pool, blocks a failed replay did not reach are replayed next time
+func (p *progress) flush() error {
+	if !p.handedOK {
+		return nil
+	}
+	return p.save(p.handed)
+}
+
+func (p *progress) save(block uint64) error {
+	if lowest, ok := p.pool.lowestPending(); ok && lowest <= block {
+		if lowest == 0 {
+			return nil
+		}
+		block = lowest - 1
+	}
+	if p.ok && block <= p.saved {
+		return nil
+	}
+
+	if err := p.store.Save(block); err != nil {
+		return err
+	}
+	p.saved, p.ok = block, true
+	return nil
+}
@@ -0,0 +1,101 @@
+package contract
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/checkpoint"
+)
+
+func newTestProgress(t *testing.T) (*progress, *checkpoint.Store) {
+	t.Helper()
+	store := checkpoint.New(filepath.Join(t.TempDir(), "checkpoint.json"))
+	pool := &workerPool{pending: make(map[taskKey]*job)}
+	return &progress{store: store, pool: pool}, store
+}
+
+func addPending(p *progress, block uint64) {
+	task := &helloworld.HelloWorldNewTaskCreated{Raw: types.Log{BlockNumber: block, BlockHash: common.BigToHash(common.Big1), Index: uint(block)}}
+	p.pool.pending[keyOf(task)] = &job{task: task}
+}
+
+func loadCheckpoint(t *testing.T, store *checkpoint.Store) (uint64, bool) {
+	t.Helper()
+	block, ok, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return block, ok
+}
+
+func TestProgressSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		saved   []uint64
+		pending []uint64
+		want    uint64
+		wantOK  bool
+	}{
+		{name: "no pending tasks", saved: []uint64{10}, want: 10, wantOK: true},
+		{name: "clamps below lowest pending", saved: []uint64{10}, pending: []uint64{7, 9}, want: 6, wantOK: true},
+		{name: "pending after block", saved: []uint64{10}, pending: []uint64{11}, want: 10, wantOK: true},
+		{name: "pending at genesis", saved: []uint64{10}, pending: []uint64{0}},
+		{name: "never moves back", saved: []uint64{10, 8}, want: 10, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, store := newTestProgress(t)
+			for _, block := range tt.pending {
+				addPending(p, block)
+			}
+			for _, block := range tt.saved {
+				if err := p.save(block); err != nil {
+					t.Fatalf("save(%d): %v", block, err)
+				}
+			}
+			got, ok := loadCheckpoint(t, store)
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("checkpoint = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestProgressFlush(t *testing.T) {
+	p, store := newTestProgress(t)
+	p.saved, p.ok = 99, true
+	p.handed, p.handedOK = 99, true
+
+	// A chunk is handed to the pool while one of its tasks is still running
+	addPending(p, 120)
+	if err := p.queued(149); err != nil {
+		t.Fatalf("queued: %v", err)
+	}
+	if got, _ := loadCheckpoint(t, store); got != 119 {
+		t.Fatalf("checkpoint = %d, want 119", got)
+	}
+
+	// Once the pool drained, flush saves up to the last handed chunk and not
+	// past it, blocks a failed replay did not reach are replayed next time
+	clear(p.pool.pending)
+	if err := p.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got, _ := loadCheckpoint(t, store); got != 149 {
+		t.Fatalf("checkpoint = %d, want 149", got)
+	}
+}
+
+func TestProgressFlushNothingHanded(t *testing.T) {
+	p, store := newTestProgress(t)
+	if err := p.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if _, ok := loadCheckpoint(t, store); ok {
+		t.Fatal("flush saved a checkpoint although nothing was handed to the pool")
+	}
+}
@@ -0,0 +1,84 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+)
+
+// taskKey identifies a single NewTaskCreated log. A task re-included in a
+// different block after a reorg gets a new key and is processed again.
+type taskKey struct {
+	blockHash common.Hash
+	logIndex  uint
+}
+
+func keyOf(task *helloworld.HelloWorldNewTaskCreated) taskKey {
+	return taskKey{blockHash: task.Raw.BlockHash, logIndex: task.Raw.Index}
+}
+
+// pendingTasks holds tasks waiting for enough confirmations
+type pendingTasks struct {
+	tasks map[taskKey]*helloworld.HelloWorldNewTaskCreated
+}
+
+func newPendingTasks() *pendingTasks {
+	return &pendingTasks{tasks: make(map[taskKey]*helloworld.HelloWorldNewTaskCreated)}
+}
+
+// add queues a task, adding the same log twice is a no-op
+func (p *pendingTasks) add(task *helloworld.HelloWorldNewTaskCreated) {
+	p.tasks[keyOf(task)] = task
+}
+
+// remove drops a task whose block was orphaned, it reports whether the task was still queued
+func (p *pendingTasks) remove(task *helloworld.HelloWorldNewTaskCreated) bool {
+	key := keyOf(task)
+	if _, ok := p.tasks[key]; !ok {
+		return false
+	}
+	delete(p.tasks, key)
+	return true
+}
+
+// ready removes and returns tasks with at least confirmations blocks on top of
+// them at given head, ordered the way they were created
+func (p *pendingTasks) ready(head, confirmations uint64) []*helloworld.HelloWorldNewTaskCreated {
+	var ready []*helloworld.HelloWorldNewTaskCreated
+	for key, task := range p.tasks {
+		if task.Raw.BlockNumber+confirmations <= head {
+			ready = append(ready, task)
+			delete(p.tasks, key)
+		}
+	}
+
+	sort.Slice(ready, func(i, j int) bool {
+		if ready[i].Raw.BlockNumber != ready[j].Raw.BlockNumber {
+			return ready[i].Raw.BlockNumber < ready[j].Raw.BlockNumber
+		}
+		return ready[i].Raw.Index < ready[j].Raw.Index
+	})
+	return ready
+}
+
+// canonical reports whether the block a task was emitted in is still part of the canonical chain
+func (s *Service) canonical(ctx context.Context, task *helloworld.HelloWorldNewTaskCreated) (bool, error) {
+	header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(task.Raw.BlockNumber))
+	if err != nil {
+		return false, errors.Wrapf(err, "Error while getting header of block %d", task.Raw.BlockNumber)
+	}
+	return header.Hash() == task.Raw.BlockHash, nil
+}
+
+// safeHead returns the highest block with enough confirmations at given head
+func safeHead(head, confirmations uint64) uint64 {
+	if head < confirmations {
+		return 0
+	}
+	return head - confirmations
+}
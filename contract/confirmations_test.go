@@ -0,0 +1,67 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+)
+
+func TestSafeHead(t *testing.T) {
+	tests := []struct {
+		head, confirmations, want uint64
+	}{
+		{head: 100, confirmations: 0, want: 100},
+		{head: 100, confirmations: 2, want: 98},
+		{head: 2, confirmations: 2, want: 0},
+		{head: 1, confirmations: 2, want: 0},
+		{head: 0, confirmations: 0, want: 0},
+	}
+	for _, tt := range tests {
+		if got := safeHead(tt.head, tt.confirmations); got != tt.want {
+			t.Fatalf("safeHead(%d, %d) = %d, want %d", tt.head, tt.confirmations, got, tt.want)
+		}
+	}
+}
+
+func newTask(block uint64, hash byte, index uint) *helloworld.HelloWorldNewTaskCreated {
+	return &helloworld.HelloWorldNewTaskCreated{Raw: types.Log{BlockNumber: block, BlockHash: common.Hash{hash}, Index: index}}
+}
+
+func TestPendingTasksReady(t *testing.T) {
+	pending := newPendingTasks()
+	for _, task := range []*helloworld.HelloWorldNewTaskCreated{
+		newTask(12, 0xc, 0),
+		newTask(10, 0xa, 3),
+		newTask(10, 0xa, 1),
+		newTask(11, 0xb, 0),
+	} {
+		pending.add(task)
+	}
+	// Adding the same log twice is a no-op
+	pending.add(newTask(10, 0xa, 1))
+
+	ready := pending.ready(12, 2)
+	if len(ready) != 2 {
+		t.Fatalf("ready = %d tasks, want 2", len(ready))
+	}
+	if ready[0].Raw.Index != 1 || ready[1].Raw.Index != 3 {
+		t.Fatalf("ready is not in log order: %d, %d", ready[0].Raw.Index, ready[1].Raw.Index)
+	}
+	if again := pending.ready(12, 2); len(again) != 0 {
+		t.Fatalf("ready returned %d tasks twice", len(again))
+	}
+
+	// A task re-included in another block after a reorg is a different task
+	if pending.remove(newTask(11, 0xd, 0)) {
+		t.Fatal("remove dropped a task of another block hash")
+	}
+	if !pending.remove(newTask(11, 0xb, 0)) {
+		t.Fatal("remove did not find the orphaned task")
+	}
+	if ready := pending.ready(100, 2); len(ready) != 1 || ready[0].Raw.BlockNumber != 12 {
+		t.Fatalf("ready = %d tasks, want only the task of block 12", len(ready))
+	}
+}
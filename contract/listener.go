@@ -0,0 +1,209 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// headBufferSize is how many new heads can queue up while ingestion is blocked
+const headBufferSize = 64
+
+func (s *Service) listen(ctx context.Context, accounts Accounts, cfg ListenerConfig) error {
+	// Subscribe before reading head so nothing created during replay is missed
+	tasks := make(chan *helloworld.HelloWorldNewTaskCreated, taskBufferSize)
+	sub, err := s.helloWorld.WatchNewTaskCreated(nil, tasks, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error while subscribing for logs")
+	}
+	defer sub.Unsubscribe()
+
+	heads := make(chan *types.Header, headBufferSize)
+	headSub, err := s.client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return errors.Wrap(err, "Error while subscribing for new heads")
+	}
+	defer headSub.Unsubscribe()
+
+	head, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting block number")
+	}
+	s.checkWeight(ctx, cfg.WeightGuard, head)
+
+	from, err := cfg.StartBlock(head)
+	if err != nil {
+		return err
+	}
+
+	submitters := &rotation{submitters: accounts.Submitters}
+	pool := newWorkerPool(ctx, cfg.Workers, cfg.QueueSize, s.logger, func(ctx context.Context, task *helloworld.HelloWorldNewTaskCreated) error {
+		return s.respondToTask(ctx, accounts, submitters.pick(), cfg, task)
+	})
+	progress := &progress{store: cfg.Checkpoint, pool: pool}
+	if from > 0 {
+		progress.saved, progress.ok = from-1, true
+		progress.handed, progress.handedOK = from-1, true
+	}
+
+	// Whatever made listening stop, let queued responses finish and record how
+	// far they got, never past blocks that were not fully handed to the pool
+	defer func() {
+		pool.close()
+		if err := progress.flush(); err != nil {
+			s.logger.Printf("Error while saving checkpoint: %v\n", err)
+		}
+	}()
+
+	// Confirmed blocks are answered right away, the rest waits in pending
+	safe := safeHead(head, cfg.Confirmations)
+	if err := s.replay(ctx, pool, progress, cfg.BackfillChunkSize, from, safe); err != nil {
+		return err
+	}
+
+	pending := newPendingTasks()
+	firstLive := max(from, safe+1)
+	if firstLive <= head {
+		queue := func(task *helloworld.HelloWorldNewTaskCreated) error {
+			pending.add(task)
+			return nil
+		}
+		if err := s.filterTasks(ctx, firstLive, head, cfg.BackfillChunkSize, queue, nil); err != nil {
+			return err
+		}
+	}
+
+	advance := func() error {
+		for _, task := range pending.ready(head, cfg.Confirmations) {
+			if err := pool.submit(ctx, task); err != nil {
+				return err
+			}
+		}
+		return progress.queued(safeHead(head, cfg.Confirmations))
+	}
+	if err := advance(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return &subscriptionError{err: err}
+		case err := <-headSub.Err():
+			return &subscriptionError{err: err}
+		case header := <-heads:
+			head = header.Number.Uint64()
+			s.checkWeight(ctx, cfg.WeightGuard, head)
+			// Also picks up tasks held by a worker that checked the guard right before it resumed
+			if !cfg.WeightGuard.Paused() {
+				if err := pool.resume(ctx); err != nil {
+					return err
+				}
+			}
+			if err := advance(); err != nil {
+				return err
+			}
+		case task := <-tasks:
+			if task.Raw.Removed {
+				s.dropOrphaned(pending, pool, task)
+				continue
+			}
+			// Already handled by replay
+			if task.Raw.BlockNumber < firstLive {
+				continue
+			}
+
+			pending.add(task)
+			head = max(head, task.Raw.BlockNumber)
+			if err := advance(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// dropOrphaned handles a log removed by a reorg. If the task is included again
+// it arrives as a new log and is processed from scratch.
+func (s *Service) dropOrphaned(pending *pendingTasks, pool *workerPool, task *helloworld.HelloWorldNewTaskCreated) {
+	switch {
+	case pending.remove(task):
+		s.logger.Printf("Task %d removed by reorg of block %s before it was confirmed\n", task.TaskIndex, task.Raw.BlockHash.Hex())
+	case pool.cancel(keyOf(task)):
+		s.logger.Printf("Task %d removed by reorg of block %s, cancelling its response\n", task.TaskIndex, task.Raw.BlockHash.Hex())
+	default:
+		s.logger.Printf("Task %d removed by reorg of block %s after it was answered\n", task.TaskIndex, task.Raw.BlockHash.Hex())
+	}
+}
+
+// verifyTask reports whether a task still needs a response from accounts
+func (s *Service) verifyTask(ctx context.Context, accounts Accounts, task *helloworld.HelloWorldNewTaskCreated) (bool, error) {
+	ok, err := s.canonical(ctx, task)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		s.logger.Printf("Skipping task %d, block %s was orphaned\n", task.TaskIndex, task.Raw.BlockHash.Hex())
+		return false, nil
+	}
+
+	// A restart may land in the middle of a block that was already partly
+	// answered, possibly by another submitter
+	for _, responder := range accounts.responders() {
+		response, err := s.helloWorld.AllTaskResponses(&bind.CallOpts{Context: ctx}, responder, task.TaskIndex)
+		if err != nil {
+			return false, errors.Wrapf(err, "Error while getting response for task %d", task.TaskIndex)
+		}
+		if len(response) > 0 {
+			s.logger.Printf("Skipping task %d, already responded from %s\n", task.TaskIndex, responder.Hex())
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// respondToTask verifies a task, signs the response with the signing key and
+// sends it from submitter, running concurrently with other workers. The
+// transaction manager keeps nonces of each submitter in order. While the
+// weight guard is paused tasks are held, which keeps the checkpoint below
+// them, and answered once the weight is back.
+func (s *Service) respondToTask(ctx context.Context, accounts Accounts, submitter signer.Signer, cfg ListenerConfig, task *helloworld.HelloWorldNewTaskCreated) error {
+	s.logger.Printf("Received task: %+v", task)
+
+	if cfg.WeightGuard.Paused() {
+		s.logger.Printf("Holding task %d while the operator is below the minimum weight\n", task.TaskIndex)
+		return errPaused
+	}
+
+	ok, err := s.verifyTask(ctx, accounts, task)
+	if err != nil || !ok {
+		return err
+	}
+
+	sig, err := s.signResponse(ctx, accounts, cfg, task.Task)
+	if err != nil {
+		return errors.Wrap(err, "Error while signing message")
+	}
+
+	label := fmt.Sprintf("response to task %d", task.TaskIndex)
+	result, err := s.txm.SendAs(ctx, submitter, s.chainID, label, func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.helloWorld.RespondToTask(transactor, task.Task, task.TaskIndex, sig)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Error while responding to task %d", task.TaskIndex)
+	}
+
+	// Final failures are in the transaction journal, they must not stop other tasks
+	if result.Status != txmgr.Mined {
+		s.logger.Printf("Giving up on task %d, response is %s\n", task.TaskIndex, result.Status)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package contract
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+)
+
+// answeredSize is how many answered tasks the pool remembers to skip duplicate logs
+const answeredSize = 4096
+
+// errPaused is returned by handlers for tasks that are held until resume is called
+var errPaused = errors.New("Responses are paused")
+
+// job is a task queued for or being processed by a worker
+type job struct {
+	task   *helloworld.HelloWorldNewTaskCreated
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// workerPool answers tasks concurrently with a bounded number of workers.
+// Submitting blocks once the queue is full, which slows down ingestion
+// instead of buffering an unbounded number of tasks.
+type workerPool struct {
+	jobs   chan *job
+	wg     sync.WaitGroup
+	base   context.Context
+	handle func(context.Context, *helloworld.HelloWorldNewTaskCreated) error
+	logger *log.Logger
+
+	mu sync.Mutex
+	// pending holds queued, running, held and failed jobs. Failed jobs stay
+	// here so the checkpoint never moves past a task that was not answered.
+	pending map[taskKey]*job
+	// held holds jobs whose handler returned errPaused
+	held map[taskKey]*job
+	// answered holds recently finished tasks, a log delivered again after its
+	// job finished is not answered twice
+	answered *recentTasks
+}
+
+func newWorkerPool(ctx context.Context, workers, queueSize int, logger *log.Logger, handle func(context.Context, *helloworld.HelloWorldNewTaskCreated) error) *workerPool {
+	p := &workerPool{
+		jobs: make(chan *job, queueSize),
+		// Jobs outlive shutdown of the listener so queued responses are drained
+		base:     context.WithoutCancel(ctx),
+		handle:   handle,
+		logger:   logger,
+		pending:  make(map[taskKey]*job),
+		held:     make(map[taskKey]*job),
+		answered: newRecentTasks(answeredSize),
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// submit queues a task, blocking while the queue is full
+func (p *workerPool) submit(ctx context.Context, task *helloworld.HelloWorldNewTaskCreated) error {
+	jobCtx, cancel := context.WithCancel(p.base)
+	j := &job{task: task, ctx: jobCtx, cancel: cancel}
+
+	key := keyOf(task)
+	p.mu.Lock()
+	if _, ok := p.pending[key]; ok {
+		p.mu.Unlock()
+		cancel()
+		return nil
+	}
+	if p.answered.contains(key) {
+		p.mu.Unlock()
+		cancel()
+		p.logger.Printf("Skipping task %d, it was already answered\n", task.TaskIndex)
+		return nil
+	}
+	p.pending[key] = j
+	p.mu.Unlock()
+
+	select {
+	case p.jobs <- j:
+		return nil
+	case <-ctx.Done():
+		p.done(key, j)
+		return ctx.Err()
+	}
+}
+
+// cancel stops a queued or running task, it reports whether the task was found
+func (p *workerPool) cancel(key taskKey) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j, ok := p.pending[key]
+	if !ok {
+		return false
+	}
+	j.cancel()
+	// A held job is not queued, so no worker would ever drop it
+	if _, ok := p.held[key]; ok {
+		delete(p.held, key)
+		delete(p.pending, key)
+	}
+	return true
+}
+
+// resume queues held jobs again, blocking while the queue is full. Jobs not
+// queued when ctx is done stay held and pending.
+func (p *workerPool) resume(ctx context.Context) error {
+	p.mu.Lock()
+	held := make([]*job, 0, len(p.held))
+	for _, j := range p.held {
+		held = append(held, j)
+	}
+	p.mu.Unlock()
+
+	for _, j := range held {
+		key := keyOf(j.task)
+		p.mu.Lock()
+		_, ok := p.held[key]
+		delete(p.held, key)
+		p.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		select {
+		case p.jobs <- j:
+		case <-ctx.Done():
+			p.hold(key, j)
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// lowestPending returns the lowest block of a task that is not answered yet
+func (p *workerPool) lowestPending() (uint64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var lowest uint64
+	found := false
+	for _, j := range p.pending {
+		if !found || j.task.Raw.BlockNumber < lowest {
+			lowest = j.task.Raw.BlockNumber
+			found = true
+		}
+	}
+	return lowest, found
+}
+
+// close stops accepting tasks and waits for queued ones to finish
+func (p *workerPool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+func (p *workerPool) work() {
+	defer p.wg.Done()
+
+	for j := range p.jobs {
+		key := keyOf(j.task)
+		if j.ctx.Err() != nil {
+			p.logger.Printf("Cancelled response to task %d\n", j.task.TaskIndex)
+			p.done(key, j)
+			continue
+		}
+
+		err := p.handle(j.ctx, j.task)
+		if err == nil && j.ctx.Err() == nil {
+			p.finish(key, j)
+			continue
+		}
+		if j.ctx.Err() != nil {
+			p.done(key, j)
+			continue
+		}
+		if errors.Is(err, errPaused) {
+			p.hold(key, j)
+			continue
+		}
+
+		// The job stays pending, so the checkpoint holds below the task and a
+		// restart answers it again
+		p.logger.Printf("Error while responding to task %d, leaving it pending: %v\n", j.task.TaskIndex, err)
+	}
+}
+
+// hold keeps j pending without queueing it until resume is called
+func (p *workerPool) hold(key taskKey, j *job) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending[key] == j {
+		p.held[key] = j
+	}
+}
+
+// finish drops j from pending and remembers it as answered
+func (p *workerPool) finish(key taskKey, j *job) {
+	j.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending[key] == j {
+		delete(p.pending, key)
+	}
+	p.answered.add(key)
+}
+
+func (p *workerPool) done(key taskKey, j *job) {
+	j.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending[key] == j {
+		delete(p.pending, key)
+	}
+}
+
+// recentTasks is a set of task keys bounded to size, once full adding a key
+// forgets the oldest one
+type recentTasks struct {
+	keys  map[taskKey]struct{}
+	order []taskKey
+	next  int
+}
+
+func newRecentTasks(size int) *recentTasks {
+	return &recentTasks{
+		keys:  make(map[taskKey]struct{}, size),
+		order: make([]taskKey, 0, size),
+	}
+}
+
+func (r *recentTasks) add(key taskKey) {
+	if r.contains(key) {
+		return
+	}
+	if len(r.order) < cap(r.order) {
+		r.order = append(r.order, key)
+	} else {
+		delete(r.keys, r.order[r.next])
+		r.order[r.next] = key
+		r.next = (r.next + 1) % len(r.order)
+	}
+	r.keys[key] = struct{}{}
+}
+
+func (r *recentTasks) contains(key taskKey) bool {
+	_, ok := r.keys[key]
+	return ok
+}
@@ -0,0 +1,151 @@
+package contract
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+)
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func pendingCount(p *workerPool) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pending)
+}
+
+func heldCount(p *workerPool) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.held)
+}
+
+func TestWorkerPoolFailedTaskStaysPending(t *testing.T) {
+	pool := newWorkerPool(context.Background(), 2, 4, log.New(io.Discard, "", 0), func(ctx context.Context, task *helloworld.HelloWorldNewTaskCreated) error {
+		if task.Raw.BlockNumber == 11 {
+			return errors.New("reverted")
+		}
+		return nil
+	})
+	for _, block := range []uint64{10, 11, 12} {
+		if err := pool.submit(context.Background(), newTask(block, byte(block), 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The failure neither stops the pool nor drops the task
+	waitFor(t, "answered tasks", func() bool { return pendingCount(pool) == 1 })
+	pool.close()
+	if lowest, ok := pool.lowestPending(); !ok || lowest != 11 {
+		t.Fatalf("lowestPending = %d, %v, want the failed task at 11", lowest, ok)
+	}
+}
+
+func TestWorkerPoolHoldAndResume(t *testing.T) {
+	var paused atomic.Bool
+	paused.Store(true)
+	var mu sync.Mutex
+	answered := map[uint64]bool{}
+	pool := newWorkerPool(context.Background(), 2, 4, log.New(io.Discard, "", 0), func(ctx context.Context, task *helloworld.HelloWorldNewTaskCreated) error {
+		if paused.Load() {
+			return errPaused
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		answered[task.Raw.BlockNumber] = true
+		return nil
+	})
+	defer pool.close()
+
+	for _, block := range []uint64{10, 11, 12} {
+		if err := pool.submit(context.Background(), newTask(block, byte(block), 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, "held tasks", func() bool { return heldCount(pool) == 3 })
+
+	// Held tasks keep the checkpoint below them
+	if lowest, ok := pool.lowestPending(); !ok || lowest != 10 {
+		t.Fatalf("lowestPending = %d, %v, want 10", lowest, ok)
+	}
+
+	// A reorg drops a held task for good
+	if !pool.cancel(keyOf(newTask(11, 11, 0))) {
+		t.Fatal("cancel did not find the held task")
+	}
+	if pendingCount(pool) != 2 {
+		t.Fatalf("pending = %d tasks, want 2 after cancelling a held task", pendingCount(pool))
+	}
+
+	paused.Store(false)
+	if err := pool.resume(context.Background()); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	waitFor(t, "answered tasks", func() bool { return pendingCount(pool) == 0 })
+	mu.Lock()
+	defer mu.Unlock()
+	if !answered[10] || answered[11] || !answered[12] {
+		t.Fatalf("answered = %v, want blocks 10 and 12", answered)
+	}
+}
+
+func TestWorkerPoolSkipsAnsweredTask(t *testing.T) {
+	var calls atomic.Int32
+	pool := newWorkerPool(context.Background(), 1, 4, log.New(io.Discard, "", 0), func(ctx context.Context, task *helloworld.HelloWorldNewTaskCreated) error {
+		calls.Add(1)
+		return nil
+	})
+	defer pool.close()
+
+	task := newTask(10, 0xa, 0)
+	if err := pool.submit(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "answered task", func() bool { return pendingCount(pool) == 0 })
+
+	// The same log delivered again, e.g. by a resubscription
+	if err := pool.submit(context.Background(), newTask(10, 0xa, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if pendingCount(pool) != 0 {
+		t.Fatal("answered task was queued again")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler called %d times, want once", calls.Load())
+	}
+}
+
+func TestRecentTasks(t *testing.T) {
+	recent := newRecentTasks(2)
+	a, b, c := keyOf(newTask(1, 1, 0)), keyOf(newTask(2, 2, 0)), keyOf(newTask(3, 3, 0))
+
+	recent.add(a)
+	recent.add(b)
+	recent.add(a)
+	if !recent.contains(a) || !recent.contains(b) {
+		t.Fatal("recent tasks lost a key below its size")
+	}
+
+	recent.add(c)
+	if recent.contains(a) || !recent.contains(b) || !recent.contains(c) {
+		t.Fatalf("recent tasks = %v, want the oldest key forgotten", recent.keys)
+	}
+}
@@ -2,10 +2,6 @@ package contract
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"crypto/rand"
-	"crypto/sha256"
-	"fmt"
 	"log"
 	"math/big"
 
@@ -13,25 +9,26 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 
 	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/chain"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
 )
 
 // Service for smart contract events
 type Service struct {
-	gasLimit          uint64
-	gasPrice          *big.Int
 	chainID           *big.Int
 	helloWorld        *helloworld.HelloWorld
 	helloWorldAddress common.Address
-	client            *ethclient.Client
+	client            *chain.Client
 	logger            *log.Logger
+	txm               *txmgr.Manager
 }
 
 // New returns a new Service for smart contract events
-func New(client *ethclient.Client, logger *log.Logger, gasLimit uint64, gasPrice *big.Int, smartContractAddress string) (*Service, error) {
+func New(client *chain.Client, logger *log.Logger, txm *txmgr.Manager, smartContractAddress string) (*Service, error) {
 	chainID, err := client.NetworkID(context.Background())
 	if err != nil {
 		return nil, errors.Wrap(err, "Error while getting network id")
@@ -43,104 +40,76 @@ func New(client *ethclient.Client, logger *log.Logger, gasLimit uint64, gasPrice
 		return nil, errors.Wrap(err, "Error while creating smar")
 	}
 	return &Service{
-		gasLimit:          gasLimit,
 		chainID:           chainID,
 		helloWorld:        contract,
 		helloWorldAddress: helloWorldAddress,
 		client:            client,
 		logger:            logger,
+		txm:               txm,
 	}, nil
 }
 
 // CreateNewTask calls create_task on smart contract
-func (s *Service) CreateNewTask(pk *ecdsa.PrivateKey, name string) error {
-	transactor, err := s.transactor(pk)
-	if err != nil {
-		return err
-	}
-
+func (s *Service) CreateNewTask(account signer.Signer, name string) error {
 	// Call the contract function
-	tx, err := s.helloWorld.CreateNewTask(transactor, name)
+	result, err := s.txm.SendAs(context.Background(), account, s.chainID, "create_task", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.helloWorld.CreateNewTask(transactor, name)
+	})
 	if err != nil {
 		return errors.Wrap(err, "Error while calling create_task")
 	}
-
-	if err = s.client.SendTransaction(context.Background(), tx); err != nil {
-		return errors.Wrap(err, "Error while sending transaction")
+	if result.Status != txmgr.Mined {
+		return errors.Errorf("create_task transaction %s is %s", result.Tx.Hash().Hex(), result.Status)
 	}
 
-	s.logger.Printf("New task created, tx hash: %s\n", tx.Hash().Hex())
+	s.logger.Printf("New task created, tx hash: %s\n", result.Tx.Hash().Hex())
 	return nil
 }
 
-func (s *Service) transactor(pk *ecdsa.PrivateKey) (*bind.TransactOpts, error) {
-	publicKey := pk.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, errors.New("Could not create public key from private key")
-	}
-
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := s.client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error while getting nonce")
-	}
-
-	transactor, err := bind.NewKeyedTransactorWithChainID(pk, s.chainID)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error while creating transactor")
-	}
-	transactor.Nonce = big.NewInt(int64(nonce))
-	transactor.Value = big.NewInt(0)
-	transactor.GasLimit = s.gasLimit
-	transactor.GasPrice = s.gasPrice
-
-	return transactor, nil
-}
-
 // StartListeningForEvents is watching smart contract events
-func (s *Service) StartListeningForEvents(pk *ecdsa.PrivateKey) error {
-	tasks := make(chan *helloworld.HelloWorldNewTaskCreated)
-	sub, err := s.helloWorld.WatchNewTaskCreated(nil, tasks, nil)
-	if err != nil {
-		return errors.Wrap(err, "Error while subscribing for logs")
+//
+// Tasks created since the last checkpoint are replayed first, then the live
+// subscription takes over from the block following the replayed head. Tasks
+// are answered once they have cfg.Confirmations blocks on top of them and their
+// block is still canonical. When the connection drops the client is redialed
+// and the missed blocks are replayed again before resubscribing.
+//
+// Responses are signed with the signing key of accounts and sent by its
+// submitters in turn. Tasks are answered by a pool of cfg.Workers workers.
+// Cancelling ctx stops ingestion, waits for queued responses to finish and
+// returns nil.
+func (s *Service) StartListeningForEvents(ctx context.Context, accounts Accounts, cfg ListenerConfig) error {
+	if err := accounts.validate(); err != nil {
+		return err
 	}
 
 	for {
-		select {
-		case err := <-sub.Err():
-			return errors.Wrap(err, "Subscription error")
-		case task := <-tasks:
-			log.Printf("Received task: %+v", task)
-			transactor, err := s.transactor(pk)
-			if err != nil {
-				return err
-			}
-
-			msgEip191 := eip191Hash(fmt.Sprintf("Hello %s", task.Task.Name))
-			msg := []byte(msgEip191)
+		gen := s.client.Generation()
+		err := s.listen(ctx, accounts, cfg)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !s.connectionLost(ctx, err) {
+			return err
+		}
 
-			sig, err := signMessage(pk, msg)
-			if err != nil {
-				return errors.Wrap(err, "Error while signing message")
-			}
-			s.helloWorld.RespondToTask(transactor, task.Task, task.TaskIndex, sig)
+		s.logger.Printf("Lost connection while listening for events: %v\n", err)
+		if err := s.client.Reconnect(ctx, gen); err != nil {
+			return errors.Wrap(err, "Error while reconnecting")
 		}
 	}
 }
 
-func signMessage(pk *ecdsa.PrivateKey, message []byte) ([]byte, error) {
-	hash := sha256.Sum256(message)
-
-	r, s, err := ecdsa.Sign(rand.Reader, pk, hash[:])
+// StakeRegistry returns the address of the stake registry of the service manager
+func (s *Service) StakeRegistry(ctx context.Context) (common.Address, error) {
+	registry, err := s.helloWorld.StakeRegistry(&bind.CallOpts{Context: ctx})
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign message: %v", err)
+		return common.Address{}, errors.Wrap(err, "Error while getting stake registry address")
 	}
-
-	return append(r.Bytes(), s.Bytes()...), nil
+	return registry, nil
 }
 
-func eip191Hash(msg string) string {
-	hash := crypto.Keccak256Hash([]byte(msg))
-	return fmt.Sprintf("\x19\x01%s", hash.Hex())
+// ConnectionState returns the state of the RPC connection
+func (s *Service) ConnectionState() chain.State {
+	return s.client.State()
 }
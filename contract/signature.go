@@ -0,0 +1,169 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// SignatureScheme selects the digest task responses are signed over
+type SignatureScheme string
+
+const (
+	// EIP191 signs keccak256("Hello, " + name) with the Ethereum signed message
+	// prefix, which is what the HelloWorldServiceManager recovers
+	EIP191 SignatureScheme = "eip191"
+	// EIP712 signs a TaskResponse typed data struct bound to the service
+	// manager address and chain, for service managers verifying EIP-712
+	EIP712 SignatureScheme = "eip712"
+)
+
+// SignatureFormat selects how the signature is encoded in the response
+type SignatureFormat string
+
+const (
+	// RawFormat submits the 65 byte signature, for service managers that
+	// recover the signer and compare it with the sender
+	RawFormat SignatureFormat = "raw"
+	// StakeRegistryFormat submits abi.encode(address[] operators, bytes[]
+	// signatures, uint32 referenceBlock) as ECDSAStakeRegistry.isValidSignature
+	// expects, which checks the signature against the operator's signing key
+	// at the reference block. Needed when submitters differ from the operator.
+	StakeRegistryFormat SignatureFormat = "stake-registry"
+)
+
+var stakeRegistrySignatureArgs = mustArguments("address[]", "bytes[]", "uint32")
+
+// ParseSignatureFormat returns the format with given name
+func ParseSignatureFormat(name string) (SignatureFormat, error) {
+	switch format := SignatureFormat(name); format {
+	case RawFormat, StakeRegistryFormat:
+		return format, nil
+	default:
+		return "", errors.Errorf("Unknown signature format %q", name)
+	}
+}
+
+// ParseSignatureScheme returns the scheme with given name
+func ParseSignatureScheme(name string) (SignatureScheme, error) {
+	switch scheme := SignatureScheme(name); scheme {
+	case EIP191, EIP712:
+		return scheme, nil
+	default:
+		return "", errors.Errorf("Unknown signature scheme %q", name)
+	}
+}
+
+// responseMessage is the message the operator attests to for a task
+func responseMessage(name string) string {
+	return fmt.Sprintf("Hello, %s", name)
+}
+
+// responseDigest returns the digest the response to the task with given name is signed over
+func (s *Service) responseDigest(scheme SignatureScheme, name string) (common.Hash, error) {
+	switch scheme {
+	case EIP712:
+		return signer.TypedDataHash(s.responseTypedData(name))
+	case EIP191, "":
+		// Same as keccak256(abi.encodePacked("Hello, ", name)).toEthSignedMessageHash()
+		return signer.TextHash(responseHash(name).Bytes()), nil
+	default:
+		return common.Hash{}, errors.Errorf("Unknown signature scheme %q", scheme)
+	}
+}
+
+// responseHash is the keccak256 hash of the response message
+func responseHash(name string) common.Hash {
+	return signer.Keccak256([]byte(responseMessage(name)))
+}
+
+func (s *Service) responseTypedData(name string) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"TaskResponse": {
+				{Name: "message", Type: "string"},
+			},
+		},
+		PrimaryType: "TaskResponse",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "HelloWorldServiceManager",
+			Version:           "1",
+			ChainId:           (*math.HexOrDecimal256)(s.chainID),
+			VerifyingContract: s.helloWorldAddress.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"message": responseMessage(name),
+		},
+	}
+}
+
+// signResponse signs the response to task with the signing key in effect at
+// the block the task was created in, checks the signature recovers to that key
+// and encodes it in the configured format. A signature by any other key, like
+// one rotated out before that block, is rejected.
+func (s *Service) signResponse(ctx context.Context, accounts Accounts, cfg ListenerConfig, task helloworld.IHelloWorldServiceManagerTask) ([]byte, error) {
+	digest, err := s.responseDigest(cfg.SignatureScheme, task.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := accounts.SigningKeys.At(uint64(task.TaskCreatedBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	var sig []byte
+	if cfg.SignatureScheme == EIP712 {
+		sig, err = key.SignTypedData(ctx, s.responseTypedData(task.Name))
+	} else {
+		sig, err = key.SignText(ctx, responseHash(task.Name).Bytes())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The ring may have switched keys while this one was signing
+	expected, err := accounts.SigningKeys.At(uint64(task.TaskCreatedBlock))
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.Verify(digest, sig, expected.Address()); err != nil {
+		return nil, errors.Wrap(err, "Error while verifying response signature")
+	}
+
+	if cfg.SignatureFormat != StakeRegistryFormat {
+		return sig, nil
+	}
+	// The signing key that counts is the one registered when the task was created
+	encoded, err := stakeRegistrySignatureArgs.Pack([]common.Address{accounts.Operator}, [][]byte{sig}, task.TaskCreatedBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while encoding response signature")
+	}
+	return encoded, nil
+}
+
+func mustArguments(types ...string) abi.Arguments {
+	args := make(abi.Arguments, len(types))
+	for i, t := range types {
+		typ, err := abi.NewType(t, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		args[i] = abi.Argument{Type: typ}
+	}
+	return args
+}
@@ -0,0 +1,84 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// The service manager checks keccak256(abi.encodePacked("Hello, ", name))
+// prefixed with "\x19Ethereum Signed Message:\n32"
+func TestResponseDigestEIP191(t *testing.T) {
+	s := &Service{chainID: big.NewInt(17000), helloWorldAddress: common.HexToAddress("0x01")}
+	for _, name := range []string{"", "Alice", "task #7"} {
+		messageHash := crypto.Keccak256([]byte("Hello, " + name))
+		want := crypto.Keccak256Hash([]byte("\x19Ethereum Signed Message:\n32"), messageHash)
+
+		for _, scheme := range []SignatureScheme{EIP191, ""} {
+			got, err := s.responseDigest(scheme, name)
+			if err != nil {
+				t.Fatalf("responseDigest(%q, %q): %v", scheme, name, err)
+			}
+			if got != want {
+				t.Fatalf("responseDigest(%q, %q) = %s, want %s", scheme, name, got.Hex(), want.Hex())
+			}
+		}
+	}
+}
+
+func TestResponseDigestEIP712(t *testing.T) {
+	s := &Service{chainID: big.NewInt(17000), helloWorldAddress: common.HexToAddress("0x3aAde2dCD2Df6a8cAc689EE797591b2913658659")}
+	got, err := s.responseDigest(EIP712, "Alice")
+	if err != nil {
+		t.Fatalf("responseDigest: %v", err)
+	}
+
+	domainType := crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	domain := crypto.Keccak256(
+		domainType,
+		crypto.Keccak256([]byte("HelloWorldServiceManager")),
+		crypto.Keccak256([]byte("1")),
+		common.LeftPadBytes(s.chainID.Bytes(), 32),
+		common.LeftPadBytes(s.helloWorldAddress.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("TaskResponse(string message)")),
+		crypto.Keccak256([]byte("Hello, Alice")),
+	)
+	want := crypto.Keccak256Hash([]byte{0x19, 0x01}, domain, structHash)
+	if got != want {
+		t.Fatalf("responseDigest = %s, want %s", got.Hex(), want.Hex())
+	}
+
+	// Another chain or service manager gives another digest
+	other := &Service{chainID: big.NewInt(1), helloWorldAddress: s.helloWorldAddress}
+	if digest, _ := other.responseDigest(EIP712, "Alice"); digest == got {
+		t.Fatal("responseDigest does not depend on the chain id")
+	}
+}
+
+func TestResponseSignatureRecovers(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Service{chainID: big.NewInt(17000)}
+	digest, err := s.responseDigest(EIP191, "Alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := signer.Sign(pk, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := sig[64]; v != 27 && v != 28 {
+		t.Fatalf("v = %d, want 27 or 28", v)
+	}
+	if err := signer.Verify(digest, sig, crypto.PubkeyToAddress(pk.PublicKey)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
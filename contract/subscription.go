@@ -0,0 +1,45 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// pingTimeout bounds the health check done after a failure
+const pingTimeout = 10 * time.Second
+
+// subscriptionError is returned when the live event subscription drops
+type subscriptionError struct {
+	err error
+}
+
+func (e *subscriptionError) Error() string {
+	if e.err == nil {
+		return "Subscription closed"
+	}
+	return "Subscription error: " + e.err.Error()
+}
+
+func (e *subscriptionError) Unwrap() error {
+	return e.err
+}
+
+// connectionLost reports whether err was caused by a dropped connection rather
+// than by a failure that a reconnect would not fix
+func (s *Service) connectionLost(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var subErr *subscriptionError
+	if errors.As(err, &subErr) {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	_, pingErr := s.client.BlockNumber(ctx)
+	return pingErr != nil
+}
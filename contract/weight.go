@@ -0,0 +1,71 @@
+package contract
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// WeightGuard holds responses while the operator is below the minimum weight
+// of the stake registry, since the service manager rejects responses from
+// such operators. A nil WeightGuard never pauses.
+type WeightGuard struct {
+	operator common.Address
+	every    uint64
+
+	paused  atomic.Bool
+	checked bool
+	last    uint64
+}
+
+// NewWeightGuard returns a guard checking the weight of operator every given
+// number of blocks
+func NewWeightGuard(operator common.Address, every uint64) *WeightGuard {
+	return &WeightGuard{operator: operator, every: max(every, 1)}
+}
+
+// Paused reports whether tasks are held instead of answered
+func (g *WeightGuard) Paused() bool {
+	return g != nil && g.paused.Load()
+}
+
+// due reports whether the weight has to be checked at head
+func (g *WeightGuard) due(head uint64) bool {
+	return g != nil && (!g.checked || head >= g.last+g.every)
+}
+
+// checkWeight updates the guard with the weight of the operator at head and
+// raises an alert when the operator pauses or resumes. Errors keep the
+// previous state, the next block checks again.
+func (s *Service) checkWeight(ctx context.Context, guard *WeightGuard, head uint64) {
+	if !guard.due(head) {
+		return
+	}
+
+	ok, err := s.HasMinimumWeight(ctx, guard.operator)
+	if err != nil {
+		s.logger.Printf("Error while checking operator weight at block %d: %v\n", head, err)
+		return
+	}
+	first := !guard.checked
+	guard.checked, guard.last = true, head
+
+	switch paused := guard.paused.Swap(!ok); {
+	case !ok && (first || !paused):
+		s.logger.Printf("ALERT: operator %s is below the minimum weight at block %d, holding tasks without answering them\n", guard.operator.Hex(), head)
+	case ok && paused:
+		s.logger.Printf("Operator %s has the minimum weight again at block %d, answering held tasks\n", guard.operator.Hex(), head)
+	}
+}
+
+// HasMinimumWeight reports whether operator has the minimum weight the service manager requires
+func (s *Service) HasMinimumWeight(ctx context.Context, operator common.Address) (bool, error) {
+	ok, err := s.helloWorld.OperatorHasMinimumWeight(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return false, errors.Wrap(err, "Error while checking operator minimum weight")
+	}
+	return ok, nil
+}
@@ -0,0 +1,170 @@
+package eigen
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	avsdirectory "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AVSDirectory"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	helloworld "github.com/patiee/avs-go-operator/abis"
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// registeredStatus is the AVS directory status of an operator registered with the AVS
+const registeredStatus = 1
+
+// AVS holds the contracts of the AVS the operator registers with
+type AVS struct {
+	ServiceManager common.Address
+	Directory      common.Address
+	StakeRegistry  common.Address
+
+	serviceManager *helloworld.HelloWorld
+	directory      *avsdirectory.ContractAVSDirectory
+	registry       *stakeregistry.ECDSAStakeRegistry
+}
+
+// AVS looks up the AVS directory and stake registry of the service manager
+func (s *Service) AVS(ctx context.Context, serviceManager common.Address) (*AVS, error) {
+	contract, err := helloworld.NewHelloWorld(serviceManager, s.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while creating service manager")
+	}
+	directoryAddress, err := contract.AvsDirectory(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting AVS directory address")
+	}
+	directory, err := avsdirectory.NewContractAVSDirectory(directoryAddress, s.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while creating AVS directory")
+	}
+	registryAddress, err := contract.StakeRegistry(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting stake registry address")
+	}
+	registry, err := stakeregistry.NewECDSAStakeRegistry(registryAddress, s.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while creating stake registry")
+	}
+
+	return &AVS{
+		ServiceManager: serviceManager,
+		Directory:      directoryAddress,
+		StakeRegistry:  registryAddress,
+		serviceManager: contract,
+		directory:      directory,
+		registry:       registry,
+	}, nil
+}
+
+// operatorSignature signs the AVS registration digest of the AVS directory for
+// account with a random salt, valid for expiry after the latest block
+func (s *Service) operatorSignature(ctx context.Context, account signer.Signer, avs *AVS, expiry time.Duration) (helloworld.ISignatureUtilsSignatureWithSaltAndExpiry, error) {
+	operator := account.Address()
+
+	var salt [32]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, errors.Wrap(err, "Error while generating salt")
+	}
+	head, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, errors.Wrap(err, "Error while getting latest header")
+	}
+	expiresAt := new(big.Int).SetUint64(head.Time + uint64(expiry.Seconds()))
+
+	digest, err := avs.directory.CalculateOperatorAVSRegistrationDigestHash(&bind.CallOpts{Context: ctx}, operator, avs.ServiceManager, salt, expiresAt)
+	if err != nil {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, errors.Wrap(err, "Error while calculating registration digest")
+	}
+	// The digest is signed as typed data, so remote signers can show what they sign
+	data := registrationTypedData(s.chainID, avs.Directory, operator, avs.ServiceManager, salt, expiresAt)
+	local, err := signer.TypedDataHash(data)
+	if err != nil {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, err
+	}
+	if local != common.Hash(digest) {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, errors.Errorf("Registration digest %s does not match digest %s of the AVS directory", local.Hex(), common.Hash(digest).Hex())
+	}
+
+	sig, err := account.SignTypedData(ctx, data)
+	if err != nil {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, errors.Wrap(err, "Error while signing registration digest")
+	}
+	if err := signer.Verify(digest, sig, operator); err != nil {
+		return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{}, errors.Wrap(err, "Error while verifying registration signature")
+	}
+
+	return helloworld.ISignatureUtilsSignatureWithSaltAndExpiry{
+		Signature: sig,
+		Salt:      salt,
+		Expiry:    expiresAt,
+	}, nil
+}
+
+// checkRegistered checks that the AVS directory has operator registered with the AVS using salt
+func (a *AVS) checkRegistered(ctx context.Context, operator common.Address, salt [32]byte) error {
+	registered, err := a.registered(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return errors.Errorf("Operator %s is not registered with the AVS", operator.Hex())
+	}
+	spent, err := a.directory.OperatorSaltIsSpent(&bind.CallOpts{Context: ctx}, operator, salt)
+	if err != nil {
+		return errors.Wrap(err, "Error while checking registration salt")
+	}
+	if !spent {
+		return errors.Errorf("Registration salt %s of operator %s is not spent", hexutil.Encode(salt[:]), operator.Hex())
+	}
+	return nil
+}
+
+// registered reports whether the AVS directory has operator registered with the AVS
+func (a *AVS) registered(ctx context.Context, operator common.Address) (bool, error) {
+	status, err := a.directory.AvsOperatorStatus(&bind.CallOpts{Context: ctx}, a.ServiceManager, operator)
+	if err != nil {
+		return false, errors.Wrap(err, "Error while getting AVS registration status")
+	}
+	return status == registeredStatus, nil
+}
+
+// registrationTypedData is the OperatorAVSRegistration message of the AVS directory
+func registrationTypedData(chainID *big.Int, directory, operator, avs common.Address, salt [32]byte, expiry *big.Int) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"OperatorAVSRegistration": {
+				{Name: "operator", Type: "address"},
+				{Name: "avs", Type: "address"},
+				{Name: "salt", Type: "bytes32"},
+				{Name: "expiry", Type: "uint256"},
+			},
+		},
+		PrimaryType: "OperatorAVSRegistration",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "EigenLayer",
+			ChainId:           (*math.HexOrDecimal256)(chainID),
+			VerifyingContract: directory.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"operator": operator.Hex(),
+			"avs":      avs.Hex(),
+			"salt":     hexutil.Encode(salt[:]),
+			"expiry":   expiry.String(),
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package eigen
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// callBackend answers contract calls with handlers by method name. Sending
+// transactions is not implemented, so a test taking a step it should not
+// fails loudly.
+type callBackend struct {
+	bind.ContractBackend
+	abis     []*abi.ABI
+	handlers map[string]func(to common.Address, args []interface{}) []interface{}
+	// logs are returned by FilterLogs by block range, queries records the ranges asked for
+	logs    []types.Log
+	queries []ethereum.FilterQuery
+}
+
+func newCallBackend(t *testing.T, metadata ...*bind.MetaData) *callBackend {
+	t.Helper()
+	b := &callBackend{handlers: make(map[string]func(common.Address, []interface{}) []interface{})}
+	for _, m := range metadata {
+		parsed, err := m.GetAbi()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.abis = append(b.abis, parsed)
+	}
+	return b
+}
+
+// handle answers calls of method with the outputs returned by fn
+func (b *callBackend) handle(method string, fn func(to common.Address, args []interface{}) []interface{}) {
+	b.handlers[method] = fn
+}
+
+func (b *callBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (b *callBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if len(call.Data) < 4 {
+		return nil, errors.New("Call without method")
+	}
+	for _, parsed := range b.abis {
+		method, err := parsed.MethodById(call.Data[:4])
+		if err != nil {
+			continue
+		}
+		fn, ok := b.handlers[method.Name]
+		if !ok {
+			return nil, errors.Errorf("Unexpected call of %s", method.Name)
+		}
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(fn(*call.To, args)...)
+	}
+	return nil, errors.Errorf("Call of unknown method %x", call.Data[:4])
+}
+
+func (b *callBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	b.queries = append(b.queries, query)
+	var logs []types.Log
+	for _, log := range b.logs {
+		if log.BlockNumber >= query.FromBlock.Uint64() && log.BlockNumber <= query.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
@@ -0,0 +1,133 @@
+package eigen
+
+import (
+	"context"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/metadata"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// MaxStakerOptOutWindowBlocks is the longest staker opt-out window the delegation manager accepts
+const MaxStakerOptOutWindowBlocks = (180 * 24 * 60 * 60) / 12
+
+// OperatorDetails are the settings an operator registers with the delegation manager
+type OperatorDetails struct {
+	// EarningsReceiver receives rewards, the operator itself when zero
+	EarningsReceiver common.Address
+	// DelegationApprover must sign delegations to the operator, anyone can delegate when zero
+	DelegationApprover common.Address
+	// StakerOptOutWindowBlocks is how long stakers wait to undelegate, it can only be increased
+	StakerOptOutWindowBlocks uint32
+	// MetadataURI points at the operator metadata JSON
+	MetadataURI string
+	// MetadataHash is the keccak256 hash the metadata document must have, any when zero
+	MetadataHash common.Hash
+	// AllowLocalMetadata accepts metadata served from localhost, for local networks only
+	AllowLocalMetadata bool
+}
+
+func (d OperatorDetails) validate() error {
+	if d.MetadataURI == "" {
+		return errors.New("Operator metadata URI must be set")
+	}
+	if d.StakerOptOutWindowBlocks > MaxStakerOptOutWindowBlocks {
+		return errors.Errorf("Staker opt-out window of %d blocks exceeds %d", d.StakerOptOutWindowBlocks, MaxStakerOptOutWindowBlocks)
+	}
+	return nil
+}
+
+// binding returns the details of operator as the delegation manager stores them
+func (d OperatorDetails) binding(operator common.Address) delegationmanager.IDelegationManagerOperatorDetails {
+	earningsReceiver := d.EarningsReceiver
+	if earningsReceiver == (common.Address{}) {
+		earningsReceiver = operator
+	}
+	return delegationmanager.IDelegationManagerOperatorDetails{
+		DeprecatedEarningsReceiver: earningsReceiver,
+		DelegationApprover:         d.DelegationApprover,
+		StakerOptOutWindowBlocks:   d.StakerOptOutWindowBlocks,
+	}
+}
+
+// OperatorDetails returns the details operator is registered with
+func (s *Service) OperatorDetails(ctx context.Context, operator common.Address) (delegationmanager.IDelegationManagerOperatorDetails, error) {
+	details, err := s.delegation.OperatorDetails(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return details, errors.Wrap(err, "Error while getting operator details")
+	}
+	return details, nil
+}
+
+// ModifyOperatorDetails updates the details of a registered operator when they
+// differ from the registered ones, and reports whether it sent a transaction
+func (s *Service) ModifyOperatorDetails(ctx context.Context, account signer.Signer, details OperatorDetails) (bool, error) {
+	if err := details.validate(); err != nil {
+		return false, err
+	}
+	current, err := s.OperatorDetails(ctx, account.Address())
+	if err != nil {
+		return false, err
+	}
+	wanted := details.binding(account.Address())
+	if current == wanted {
+		return false, nil
+	}
+	if wanted.StakerOptOutWindowBlocks < current.StakerOptOutWindowBlocks {
+		return false, errors.Errorf("Staker opt-out window can only be increased, it is %d blocks", current.StakerOptOutWindowBlocks)
+	}
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "modifyOperatorDetails", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.delegation.ModifyOperatorDetails(transactor, wanted)
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "Error while modifying operator details")
+	}
+	if result.Status != txmgr.Mined {
+		return false, errors.Errorf("modifyOperatorDetails transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	s.logger.Printf("Modified operator details, tx hash: %s\n", result.Tx.Hash().Hex())
+	return true, nil
+}
+
+// UpdateOperatorMetadataURI announces the metadata URI of details for a
+// registered operator, after checking the document it points at
+func (s *Service) UpdateOperatorMetadataURI(ctx context.Context, account signer.Signer, details OperatorDetails) error {
+	if err := details.validate(); err != nil {
+		return err
+	}
+	if err := s.checkMetadata(ctx, details); err != nil {
+		return err
+	}
+	metadataURI := details.MetadataURI
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "updateOperatorMetadataURI", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.delegation.UpdateOperatorMetadataURI(transactor, metadataURI)
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error while updating operator metadata URI")
+	}
+	if result.Status != txmgr.Mined {
+		return errors.Errorf("updateOperatorMetadataURI transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	s.logger.Printf("Updated operator metadata URI to %s, tx hash: %s\n", metadataURI, result.Tx.Hash().Hex())
+	return nil
+}
+
+// checkMetadata fetches and validates the metadata document at the metadata URI
+func (s *Service) checkMetadata(ctx context.Context, details OperatorDetails) error {
+	hash, err := metadata.Check(ctx, details.MetadataURI, details.MetadataHash, metadata.Options{AllowLocal: details.AllowLocalMetadata})
+	if err != nil {
+		return errors.Wrap(err, "Error while checking operator metadata")
+	}
+	s.logger.Printf("Operator metadata at %s is valid, hash: %s\n", details.MetadataURI, hash.Hex())
+	return nil
+}
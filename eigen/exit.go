@@ -0,0 +1,110 @@
+package eigen
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// ExitConfig configures how the operator leaves the AVS
+type ExitConfig struct {
+	// Undelegate lists stakers delegated to the operator that are undelegated too
+	Undelegate []common.Address
+	// DryRun only logs the steps that would be taken
+	DryRun bool
+}
+
+// Exit deregisters account from the stake registry, which deregisters it from
+// the AVS too, and undelegates the stakers of cfg. Steps that are already done
+// are skipped, and the result is checked on-chain afterwards. An operator that
+// is registered with the AVS without being in the stake registry cannot leave
+// the AVS by itself, which is reported as an error. It returns the
+// registration state afterwards.
+func (s *Service) Exit(ctx context.Context, account signer.Signer, avs *AVS, cfg ExitConfig) (Registration, error) {
+	operator := account.Address()
+	registration, err := s.Registration(ctx, operator, avs)
+	if err != nil {
+		return registration, err
+	}
+	s.logger.Printf("Registration of %s\n", registration)
+
+	// The stake registry deregisters the operator from the AVS as well
+	if registration.StakeRegistry {
+		description := "deregister from stake registry " + avs.StakeRegistry.Hex() + " and AVS " + avs.ServiceManager.Hex()
+		if err := s.step(ctx, account, cfg, "deregisterOperator", description, func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+			return avs.registry.DeregisterOperator(transactor)
+		}); err != nil {
+			return registration, err
+		}
+	}
+	// Only the stake registry may deregister an operator from the AVS
+	if !registration.StakeRegistry && registration.AVS {
+		s.logger.Printf("Operator %s is registered with AVS %s but not in stake registry %s, the operator cannot deregister from the AVS itself\n", operator.Hex(), avs.ServiceManager.Hex(), avs.StakeRegistry.Hex())
+	}
+
+	for _, staker := range cfg.Undelegate {
+		delegatedTo, err := s.delegation.DelegatedTo(&bind.CallOpts{Context: ctx}, staker)
+		if err != nil {
+			return registration, errors.Wrap(err, "Error while getting delegation of staker")
+		}
+		if delegatedTo != operator {
+			s.logger.Printf("Staker %s is not delegated to %s, skipping\n", staker.Hex(), operator.Hex())
+			continue
+		}
+		staker := staker
+		if err := s.step(ctx, account, cfg, "undelegate", "undelegate staker "+staker.Hex(), func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+			return s.delegation.Undelegate(transactor, staker)
+		}); err != nil {
+			return registration, err
+		}
+	}
+	if cfg.DryRun {
+		return registration, nil
+	}
+
+	if registration, err = s.Registration(ctx, operator, avs); err != nil {
+		return registration, err
+	}
+	s.logger.Printf("Registration of %s\n", registration)
+	if registration.StakeRegistry {
+		return registration, errors.Errorf("Operator %s is still registered after exit", operator.Hex())
+	}
+	for _, staker := range cfg.Undelegate {
+		delegatedTo, err := s.delegation.DelegatedTo(&bind.CallOpts{Context: ctx}, staker)
+		if err != nil {
+			return registration, errors.Wrap(err, "Error while getting delegation of staker")
+		}
+		if delegatedTo == operator {
+			return registration, errors.Errorf("Staker %s is still delegated to %s after exit", staker.Hex(), operator.Hex())
+		}
+	}
+	if registration.AVS {
+		return registration, errors.Errorf("Operator %s is still registered with AVS %s, only stake registry %s can deregister it", operator.Hex(), avs.ServiceManager.Hex(), avs.StakeRegistry.Hex())
+	}
+	return registration, nil
+}
+
+// step sends the transaction built by fn, or only logs it in a dry run
+func (s *Service) step(ctx context.Context, account signer.Signer, cfg ExitConfig, label, description string, fn func(*bind.TransactOpts) (*types.Transaction, error)) error {
+	if cfg.DryRun {
+		s.logger.Printf("Dry run, would %s\n", description)
+		return nil
+	}
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, label, fn)
+	if err != nil {
+		return errors.Wrapf(err, "Error while calling %s", label)
+	}
+	if result.Status != txmgr.Mined {
+		return errors.Errorf("%s transaction %s is %s: %s", label, result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	s.logger.Printf("Done: %s, tx hash: %s\n", description, result.Tx.Hash().Hex())
+	return nil
+}
@@ -0,0 +1,156 @@
+package eigen
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"math/big"
+	"strings"
+	"testing"
+
+	avsdirectory "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AVSDirectory"
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// chainState is the on-chain state a fake backend answers calls from
+type chainState struct {
+	avs           bool
+	stakeRegistry bool
+	delegatedTo   map[common.Address]common.Address
+}
+
+// newTestService returns a Service and AVS reading state, and the log they write
+func newTestService(t *testing.T, state chainState) (*Service, *AVS, *bytes.Buffer) {
+	t.Helper()
+	backend := newCallBackend(t, delegationmanager.ContractDelegationManagerMetaData, avsdirectory.ContractAVSDirectoryMetaData, stakeregistry.ECDSAStakeRegistryMetaData)
+	backend.handle("isOperator", func(common.Address, []interface{}) []interface{} {
+		return []interface{}{true}
+	})
+	backend.handle("avsOperatorStatus", func(common.Address, []interface{}) []interface{} {
+		if state.avs {
+			return []interface{}{uint8(registeredStatus)}
+		}
+		return []interface{}{uint8(0)}
+	})
+	backend.handle("operatorRegistered", func(common.Address, []interface{}) []interface{} {
+		return []interface{}{state.stakeRegistry}
+	})
+	backend.handle("getLastCheckpointOperatorWeight", func(common.Address, []interface{}) []interface{} {
+		return []interface{}{big.NewInt(100)}
+	})
+	backend.handle("delegatedTo", func(_ common.Address, args []interface{}) []interface{} {
+		return []interface{}{state.delegatedTo[args[0].(common.Address)]}
+	})
+
+	delegation, err := delegationmanager.NewContractDelegationManager(common.HexToAddress("0xde"), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	directory, err := avsdirectory.NewContractAVSDirectory(common.HexToAddress("0xd1"), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := stakeregistry.NewECDSAStakeRegistry(common.HexToAddress("0x5e"), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logs := new(bytes.Buffer)
+	s := &Service{chainID: big.NewInt(17000), logger: log.New(logs, "", 0), delegation: delegation}
+	avs := &AVS{
+		ServiceManager: common.HexToAddress("0x5a"),
+		Directory:      common.HexToAddress("0xd1"),
+		StakeRegistry:  common.HexToAddress("0x5e"),
+		directory:      directory,
+		registry:       registry,
+	}
+	return s, avs, logs
+}
+
+func TestExitDryRun(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := signer.NewLocal(pk)
+	operator := account.Address()
+	delegated, other := common.HexToAddress("0xa1"), common.HexToAddress("0xa2")
+
+	tests := []struct {
+		name       string
+		state      chainState
+		undelegate []common.Address
+		steps      []string
+		skipped    []string
+	}{
+		{
+			name:  "registered",
+			state: chainState{avs: true, stakeRegistry: true},
+			steps: []string{"would deregister from stake registry"},
+		},
+		{
+			name:    "only registered with the AVS",
+			state:   chainState{avs: true},
+			skipped: []string{"the operator cannot deregister from the AVS itself"},
+		},
+		{
+			name:       "undelegates only stakers of the operator",
+			state:      chainState{delegatedTo: map[common.Address]common.Address{delegated: operator, other: common.HexToAddress("0x99")}},
+			undelegate: []common.Address{delegated, other},
+			steps:      []string{"would undelegate staker " + delegated.Hex()},
+			skipped:    []string{"Staker " + other.Hex() + " is not delegated"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, avs, logs := newTestService(t, tt.state)
+			if _, err := s.Exit(context.Background(), account, avs, ExitConfig{Undelegate: tt.undelegate, DryRun: true}); err != nil {
+				t.Fatalf("Exit: %v", err)
+			}
+
+			out := logs.String()
+			if got := strings.Count(out, "Dry run, would"); got != len(tt.steps) {
+				t.Fatalf("dry run took %d steps, want %d:\n%s", got, len(tt.steps), out)
+			}
+			for _, want := range append(tt.steps, tt.skipped...) {
+				if !strings.Contains(out, want) {
+					t.Fatalf("log does not contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+// Exit never calls deregisterOperatorFromAVS itself, only the stake registry may
+func TestExitOnlyRegisteredWithAVS(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, avs, _ := newTestService(t, chainState{avs: true})
+
+	registration, err := s.Exit(context.Background(), signer.NewLocal(pk), avs, ExitConfig{})
+	if err == nil || !strings.Contains(err.Error(), "only stake registry") {
+		t.Fatalf("Exit = %v, want an error that only the stake registry can deregister", err)
+	}
+	if !registration.AVS || registration.StakeRegistry {
+		t.Fatalf("registration = %s, want registered with the AVS only", registration)
+	}
+}
+
+func TestExitNothingToDo(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, avs, _ := newTestService(t, chainState{})
+
+	if _, err := s.Exit(context.Background(), signer.NewLocal(pk), avs, ExitConfig{}); err != nil {
+		t.Fatalf("Exit: %v", err)
+	}
+}
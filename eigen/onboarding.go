@@ -0,0 +1,237 @@
+package eigen
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/onboarding"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// OnboardingConfig configures Onboard
+type OnboardingConfig struct {
+	Registration RegistrationConfig
+	// Store persists progress so a restart resumes where onboarding stopped
+	Store *onboarding.Store
+	// Optional lists skippable steps that do not block onboarding
+	Optional []onboarding.Step
+	// PollInterval is how long to wait before checking a step again
+	PollInterval time.Duration
+	// MaxAttempts is how often a failing step is retried before giving up
+	MaxAttempts int
+}
+
+// onboardingState is the on-chain state every step is checked against
+type onboardingState struct {
+	Registration
+	// Stake reports whether shares of a restakeable strategy are delegated to the operator
+	Stake bool
+	// MinimumWeight reports whether the service manager accepts responses of the operator
+	MinimumWeight bool
+}
+
+// done reports whether step is complete in state
+func (st onboardingState) done(step onboarding.Step) bool {
+	switch step {
+	case onboarding.EigenLayer:
+		return st.EigenLayer
+	case onboarding.Deposit:
+		return st.Stake
+	case onboarding.AVS:
+		return st.AVS
+	case onboarding.StakeRegistry:
+		return st.StakeRegistry
+	case onboarding.MinimumWeight:
+		return st.MinimumWeight
+	}
+	return false
+}
+
+// progress returns the first required step that is not complete in state
+func (st onboardingState) progress(optional []onboarding.Step) onboarding.Progress {
+	progress := onboarding.Progress{Operator: st.Operator, Step: onboarding.Done, UpdatedAt: time.Now().UTC()}
+	for _, step := range onboarding.Steps {
+		switch {
+		case st.done(step):
+			progress.Completed = append(progress.Completed, step)
+		case slices.Contains(optional, step):
+			progress.Skipped = append(progress.Skipped, step)
+		default:
+			progress.Step = step
+			return progress
+		}
+	}
+	return progress
+}
+
+// onboardingState reads the state of every onboarding step of operator
+func (s *Service) onboardingState(ctx context.Context, operator common.Address, avs *AVS) (onboardingState, error) {
+	registration, err := s.Registration(ctx, operator, avs)
+	if err != nil {
+		return onboardingState{}, err
+	}
+	state := onboardingState{Registration: registration}
+
+	opts := &bind.CallOpts{Context: ctx}
+	strategies, err := avs.serviceManager.GetRestakeableStrategies(opts)
+	if err != nil {
+		return state, errors.Wrap(err, "Error while getting restakeable strategies")
+	}
+	if len(strategies) > 0 {
+		shares, err := s.delegation.GetOperatorShares(opts, operator, strategies)
+		if err != nil {
+			return state, errors.Wrap(err, "Error while getting operator shares")
+		}
+		for _, amount := range shares {
+			state.Stake = state.Stake || amount.Sign() > 0
+		}
+	}
+	if state.MinimumWeight, err = avs.serviceManager.OperatorHasMinimumWeight(opts, operator); err != nil {
+		return state, errors.Wrap(err, "Error while checking operator minimum weight")
+	}
+	return state, nil
+}
+
+// Onboarding returns the onboarding progress of operator without taking any
+// step, with what was saved about the current step
+func (s *Service) Onboarding(ctx context.Context, operator common.Address, avs *AVS, cfg OnboardingConfig) (onboarding.Progress, error) {
+	state, err := s.onboardingState(ctx, operator, avs)
+	if err != nil {
+		return onboarding.Progress{}, err
+	}
+	progress := state.progress(cfg.Optional)
+
+	saved, ok, err := cfg.Store.Load()
+	if err != nil {
+		return progress, err
+	}
+	if ok && saved.Operator == operator && saved.Step == progress.Step {
+		progress.Waiting, progress.LastError, progress.UpdatedAt = saved.Waiting, saved.LastError, saved.UpdatedAt
+	}
+	return progress, nil
+}
+
+// Onboard takes the onboarding steps of account in order until every required
+// step is complete. Steps are checked on-chain, so completed ones are skipped
+// and a restart resumes at the first missing step. Steps that depend on
+// stakers are waited for, and a step failing cfg.MaxAttempts times in a row
+// stops onboarding. Progress is saved after every check and every step taken.
+func (s *Service) Onboard(ctx context.Context, account signer.Signer, avs *AVS, cfg OnboardingConfig) (onboarding.Progress, error) {
+	operator := account.Address()
+	saved, ok, err := cfg.Store.Load()
+	if err != nil {
+		return saved, err
+	}
+	if ok && saved.Operator == operator && saved.Step != onboarding.Done {
+		s.logger.Printf("Resuming onboarding of %s at %s\n", operator.Hex(), saved.Step.Description())
+		if saved.LastError != "" {
+			s.logger.Printf("Onboarding stopped after: %s\n", saved.LastError)
+		}
+	}
+
+	current, waiting := onboarding.Step(""), ""
+	attempts := 0
+	for {
+		state, err := s.onboardingState(ctx, operator, avs)
+		if err != nil {
+			return saved, err
+		}
+		progress := state.progress(cfg.Optional)
+		if progress.Step != current {
+			if current == "" && state.EigenLayer {
+				if err := s.checkDetails(ctx, operator, cfg.Registration.Details); err != nil {
+					return progress, err
+				}
+			}
+			current, waiting, attempts = progress.Step, "", 0
+			s.logger.Printf("Registration of %s\n", state.Registration)
+		}
+		if progress.Step == onboarding.Done {
+			for _, step := range progress.Skipped {
+				s.logger.Printf("Optional onboarding step %s is not complete\n", step.Description())
+			}
+			s.logger.Printf("Onboarding of %s is complete\n", operator.Hex())
+			return progress, cfg.Store.Save(progress)
+		}
+		if waiting == "" && attempts == 0 {
+			s.logger.Printf("Onboarding step %d/%d: %s\n", progress.Number(), len(onboarding.Steps), current.Description())
+		}
+
+		stepErr := s.onboardingStep(ctx, account, avs, cfg, state, progress.Step)
+		if stepErr == nil {
+			// Record the step right away, a restart checks the next one on-chain anyway
+			progress.Completed = append(progress.Completed, progress.Step)
+			progress.Step, progress.UpdatedAt = progress.Step.Next(), time.Now().UTC()
+			if err := cfg.Store.Save(progress); err != nil {
+				return progress, err
+			}
+			continue
+		}
+		var wait waitError
+		if errors.As(stepErr, &wait) {
+			if progress.Waiting = string(wait); progress.Waiting != waiting {
+				waiting = progress.Waiting
+				s.logger.Printf("Waiting: %s\n", waiting)
+			}
+		} else {
+			attempts++
+			progress.LastError = stepErr.Error()
+		}
+		if err := cfg.Store.Save(progress); err != nil {
+			return progress, err
+		}
+		if attempts > 0 && attempts >= cfg.MaxAttempts {
+			return progress, errors.Wrapf(stepErr, "Onboarding step %s failed %d times", progress.Step.Description(), attempts)
+		}
+		if attempts > 0 {
+			s.logger.Printf("Error in onboarding step %s, attempt %d of %d: %v\n", progress.Step.Description(), attempts, cfg.MaxAttempts, stepErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return progress, ctx.Err()
+		case <-time.After(cfg.PollInterval):
+		}
+	}
+}
+
+// waitError is returned by steps that wait for something the operator cannot do itself
+type waitError string
+
+func (e waitError) Error() string {
+	return string(e)
+}
+
+// onboardingStep takes step, or returns a waitError saying what it waits for
+func (s *Service) onboardingStep(ctx context.Context, account signer.Signer, avs *AVS, cfg OnboardingConfig, state onboardingState, step onboarding.Step) error {
+	operator := account.Address()
+	switch step {
+	case onboarding.EigenLayer:
+		return s.RegisterAsOperator(account, cfg.Registration.Details)
+
+	case onboarding.Deposit:
+		return waitError(fmt.Sprintf("no shares of a restakeable strategy are delegated to %s, deposit and delegate with cmd/eigen", operator.Hex()))
+
+	case onboarding.AVS:
+		// Only the stake registry may register operators with the service manager
+		if state.StakeRegistry {
+			return waitError(fmt.Sprintf("%s is in stake registry %s but not registered with the AVS, exit the stake registry with cmd/exit to register again", operator.Hex(), avs.StakeRegistry.Hex()))
+		}
+		// The stake registry registers the operator with the AVS too
+		_, err := s.RegisterWithStakeRegistry(ctx, account, avs, cfg.Registration.SigningKey, cfg.Registration.AVSExpiry)
+		return err
+
+	case onboarding.StakeRegistry:
+		return waitError(fmt.Sprintf("%s is registered with the AVS but not in stake registry %s, only the stake registry can deregister it from the AVS", operator.Hex(), avs.StakeRegistry.Hex()))
+
+	case onboarding.MinimumWeight:
+		return waitError(fmt.Sprintf("weight of %s is below the minimum weight, see cmd/stakereport", operator.Hex()))
+	}
+	return errors.Errorf("Unknown onboarding step %q", step)
+}
@@ -0,0 +1,109 @@
+package eigen
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/onboarding"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+func TestOnboardingProgress(t *testing.T) {
+	operator := common.HexToAddress("0x01")
+	tests := []struct {
+		name      string
+		state     onboardingState
+		optional  []onboarding.Step
+		want      onboarding.Step
+		completed []onboarding.Step
+		skipped   []onboarding.Step
+	}{
+		{
+			name: "nothing done",
+			want: onboarding.EigenLayer,
+		},
+		{
+			name:      "waiting for stake",
+			state:     onboardingState{Registration: Registration{EigenLayer: true}},
+			want:      onboarding.Deposit,
+			completed: []onboarding.Step{onboarding.EigenLayer},
+		},
+		{
+			name:      "optional stake is skipped",
+			state:     onboardingState{Registration: Registration{EigenLayer: true}},
+			optional:  []onboarding.Step{onboarding.Deposit},
+			want:      onboarding.AVS,
+			completed: []onboarding.Step{onboarding.EigenLayer},
+			skipped:   []onboarding.Step{onboarding.Deposit},
+		},
+		{
+			name:      "in the stake registry but not the AVS",
+			state:     onboardingState{Registration: Registration{EigenLayer: true, StakeRegistry: true}, Stake: true},
+			want:      onboarding.AVS,
+			completed: []onboarding.Step{onboarding.EigenLayer, onboarding.Deposit},
+		},
+		{
+			name:      "below the minimum weight",
+			state:     onboardingState{Registration: Registration{EigenLayer: true, AVS: true, StakeRegistry: true}, Stake: true},
+			want:      onboarding.MinimumWeight,
+			completed: []onboarding.Step{onboarding.EigenLayer, onboarding.Deposit, onboarding.AVS, onboarding.StakeRegistry},
+		},
+		{
+			name:      "done",
+			state:     onboardingState{Registration: Registration{EigenLayer: true, AVS: true, StakeRegistry: true}, Stake: true, MinimumWeight: true},
+			want:      onboarding.Done,
+			completed: onboarding.Steps,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.state.Operator = operator
+			got := tt.state.progress(tt.optional)
+			if got.Operator != operator {
+				t.Fatalf("Operator = %s, want %s", got.Operator.Hex(), operator.Hex())
+			}
+			if got.Step != tt.want {
+				t.Fatalf("Step = %s, want %s", got.Step, tt.want)
+			}
+			if !slices.Equal(got.Completed, tt.completed) {
+				t.Fatalf("Completed = %v, want %v", got.Completed, tt.completed)
+			}
+			if !slices.Equal(got.Skipped, tt.skipped) {
+				t.Fatalf("Skipped = %v, want %v", got.Skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+// Steps the operator cannot take itself wait instead of sending a transaction
+func TestOnboardingStepWaits(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Service{}
+	avs := &AVS{StakeRegistry: common.HexToAddress("0x02")}
+	tests := []struct {
+		step  onboarding.Step
+		state onboardingState
+	}{
+		{step: onboarding.Deposit},
+		{step: onboarding.AVS, state: onboardingState{Registration: Registration{StakeRegistry: true}}},
+		{step: onboarding.StakeRegistry},
+		{step: onboarding.MinimumWeight},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.step), func(t *testing.T) {
+			err := s.onboardingStep(context.Background(), signer.NewLocal(pk), avs, OnboardingConfig{}, tt.state, tt.step)
+			var wait waitError
+			if !errors.As(err, &wait) {
+				t.Fatalf("onboardingStep = %v, want a waitError", err)
+			}
+		})
+	}
+}
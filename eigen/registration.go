@@ -0,0 +1,100 @@
+package eigen
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// RegistrationConfig configures the registration steps of onboarding
+type RegistrationConfig struct {
+	// Details are registered with the delegation manager
+	Details OperatorDetails
+	// AVSExpiry is how long the AVS registration signature stays valid
+	AVSExpiry time.Duration
+	// SigningKey is registered in the stake registry, the operator itself when zero
+	SigningKey common.Address
+}
+
+// Registration is the registration state of an operator
+type Registration struct {
+	Operator common.Address
+	// EigenLayer reports whether the delegation manager knows the operator
+	EigenLayer bool
+	// AVS reports whether the AVS directory has the operator registered with the AVS
+	AVS bool
+	// StakeRegistry reports whether the operator is a member of the stake registry
+	StakeRegistry bool
+	// Weight is the operator weight at the last stake registry checkpoint, nil when not a member
+	Weight *big.Int
+}
+
+// Complete reports whether every registration step is done
+func (r Registration) Complete() bool {
+	return r.EigenLayer && r.AVS && r.StakeRegistry
+}
+
+func (r Registration) String() string {
+	summary := fmt.Sprintf("operator %s: eigenlayer %s, avs %s, stake registry %s",
+		r.Operator.Hex(), registered(r.EigenLayer), registered(r.AVS), registered(r.StakeRegistry))
+	if r.Weight != nil {
+		summary += fmt.Sprintf(" with weight %s", r.Weight)
+	}
+	return summary
+}
+
+func registered(ok bool) string {
+	if ok {
+		return "registered"
+	}
+	return "not registered"
+}
+
+// IsOperator reports whether the delegation manager knows operator
+func (s *Service) IsOperator(ctx context.Context, operator common.Address) (bool, error) {
+	registered, err := s.delegation.IsOperator(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return false, errors.Wrap(err, "Error while checking operator registration")
+	}
+	return registered, nil
+}
+
+// Registration returns the registration state of operator with EigenLayer and the AVS
+func (s *Service) Registration(ctx context.Context, operator common.Address, avs *AVS) (Registration, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	registration := Registration{Operator: operator}
+
+	var err error
+	if registration.EigenLayer, err = s.IsOperator(ctx, operator); err != nil {
+		return registration, err
+	}
+	if registration.AVS, err = avs.registered(ctx, operator); err != nil {
+		return registration, err
+	}
+	if registration.StakeRegistry, err = avs.registry.OperatorRegistered(opts, operator); err != nil {
+		return registration, errors.Wrap(err, "Error while checking stake registry membership")
+	}
+	if registration.StakeRegistry {
+		if registration.Weight, err = s.OperatorWeight(ctx, operator, avs); err != nil {
+			return registration, err
+		}
+	}
+	return registration, nil
+}
+
+// checkDetails warns when the registered details of operator differ from details
+func (s *Service) checkDetails(ctx context.Context, operator common.Address, details OperatorDetails) error {
+	current, err := s.OperatorDetails(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if current != details.binding(operator) {
+		s.logger.Printf("Registered details of operator %s differ from config, run cmd/operatordetails to update them\n", operator.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package eigen
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	istrategy "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IStrategy"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// erc20MetadataABI covers the optional ERC-20 getters the IERC20 binding lacks
+const erc20MetadataABI = `[
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`
+
+// StrategyStake is the stake of an operator in one restakeable strategy
+type StrategyStake struct {
+	Strategy common.Address `json:"strategy"`
+	// Token is the underlying token, zero for beacon chain ETH
+	Token    common.Address `json:"token"`
+	Symbol   string         `json:"symbol"`
+	Decimals uint8          `json:"decimals"`
+	// Restaked reports whether the service manager counts the operator as restaked in the strategy
+	Restaked bool `json:"restaked"`
+	// Multiplier is the weight multiplier of the strategy in the stake registry quorum
+	Multiplier *big.Int `json:"multiplier"`
+	// Shares are delegated to the operator in the delegation manager
+	Shares *big.Int `json:"shares"`
+	// Underlying is the amount of token the shares are worth
+	Underlying *big.Int `json:"underlying"`
+}
+
+// StakeReport is the stake of an operator in the AVS at a block
+type StakeReport struct {
+	Operator         common.Address  `json:"operator"`
+	Block            uint64          `json:"block"`
+	Strategies       []StrategyStake `json:"strategies"`
+	Weight           *big.Int        `json:"weight"`
+	MinimumWeight    *big.Int        `json:"minimumWeight"`
+	ThresholdWeight  *big.Int        `json:"thresholdWeight"`
+	TotalWeight      *big.Int        `json:"totalWeight"`
+	HasMinimumWeight bool            `json:"hasMinimumWeight"`
+}
+
+// StakeReport lists every restakeable strategy of the AVS with the shares
+// delegated to operator and its weight in the stake registry, all read at
+// the same block
+func (s *Service) StakeReport(ctx context.Context, operator common.Address, avs *AVS) (StakeReport, error) {
+	report := StakeReport{Operator: operator}
+
+	head, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return report, errors.Wrap(err, "Error while getting block number")
+	}
+	report.Block = head
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(head)}
+
+	strategies, err := avs.serviceManager.GetRestakeableStrategies(opts)
+	if err != nil {
+		return report, errors.Wrap(err, "Error while getting restakeable strategies")
+	}
+	restaked, err := avs.serviceManager.GetOperatorRestakedStrategies(opts, operator)
+	if err != nil {
+		return report, errors.Wrap(err, "Error while getting operator restaked strategies")
+	}
+	shares, err := s.delegation.GetOperatorShares(opts, operator, strategies)
+	if err != nil {
+		return report, errors.Wrap(err, "Error while getting operator shares")
+	}
+	if len(shares) != len(strategies) {
+		return report, errors.Errorf("Got shares of %d strategies instead of %d", len(shares), len(strategies))
+	}
+	quorum, err := avs.registry.Quorum(opts)
+	if err != nil {
+		return report, errors.Wrap(err, "Error while getting stake registry quorum")
+	}
+	beaconChainETH, err := s.delegation.BeaconChainETHStrategy(opts)
+	if err != nil {
+		return report, errors.Wrap(err, "Error while getting beacon chain ETH strategy")
+	}
+
+	for i, strategy := range strategies {
+		stake := StrategyStake{
+			Strategy:   strategy,
+			Multiplier: new(big.Int),
+			Shares:     shares[i],
+		}
+		for _, r := range restaked {
+			stake.Restaked = stake.Restaked || r == strategy
+		}
+		for _, params := range quorum.Strategies {
+			if params.Strategy == strategy {
+				stake.Multiplier = params.Multiplier
+			}
+		}
+		if err := s.strategyToken(opts, &stake, beaconChainETH); err != nil {
+			return report, err
+		}
+		report.Strategies = append(report.Strategies, stake)
+	}
+
+	if report.Weight, err = avs.registry.GetLastCheckpointOperatorWeight(opts, operator); err != nil {
+		return report, errors.Wrap(err, "Error while getting operator weight")
+	}
+	if report.MinimumWeight, err = avs.registry.MinimumWeight(opts); err != nil {
+		return report, errors.Wrap(err, "Error while getting minimum weight")
+	}
+	if report.ThresholdWeight, err = avs.registry.GetLastCheckpointThresholdWeight(opts); err != nil {
+		return report, errors.Wrap(err, "Error while getting threshold weight")
+	}
+	if report.TotalWeight, err = avs.registry.GetLastCheckpointTotalWeight(opts); err != nil {
+		return report, errors.Wrap(err, "Error while getting total weight")
+	}
+	if report.HasMinimumWeight, err = avs.serviceManager.OperatorHasMinimumWeight(opts, operator); err != nil {
+		return report, errors.Wrap(err, "Error while checking operator minimum weight")
+	}
+	return report, nil
+}
+
+// strategyToken fills in the underlying token of stake and what its shares are worth
+func (s *Service) strategyToken(opts *bind.CallOpts, stake *StrategyStake, beaconChainETH common.Address) error {
+	if stake.Strategy == beaconChainETH {
+		stake.Symbol, stake.Decimals, stake.Underlying = "ETH", 18, stake.Shares
+		return nil
+	}
+
+	strategy, err := istrategy.NewContractIStrategy(stake.Strategy, s.client)
+	if err != nil {
+		return errors.Wrap(err, "Error while creating strategy")
+	}
+	if stake.Token, err = strategy.UnderlyingToken(opts); err != nil {
+		return errors.Wrapf(err, "Error while getting underlying token of strategy %s", stake.Strategy.Hex())
+	}
+	if stake.Underlying, err = strategy.SharesToUnderlyingView(opts, stake.Shares); err != nil {
+		return errors.Wrapf(err, "Error while converting shares of strategy %s", stake.Strategy.Hex())
+	}
+
+	// Symbol and decimals are optional in ERC-20, tokens without them are reported by address
+	parsed, err := abi.JSON(strings.NewReader(erc20MetadataABI))
+	if err != nil {
+		return errors.Wrap(err, "Error while parsing ERC-20 abi")
+	}
+	token := bind.NewBoundContract(stake.Token, parsed, s.client, nil, nil)
+	var out []interface{}
+	if err := token.Call(opts, &out, "symbol"); err == nil && len(out) == 1 {
+		stake.Symbol, _ = out[0].(string)
+	}
+	out = nil
+	if err := token.Call(opts, &out, "decimals"); err == nil && len(out) == 1 {
+		stake.Decimals, _ = out[0].(uint8)
+	}
+	return nil
+}
@@ -2,31 +2,32 @@ package eigen
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"log"
 	"math/big"
 
 	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/chain"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
 )
 
 // Service for Eigen smart contracts
 type Service struct {
 	chainID           *big.Int
-	gasLimit          uint64
-	gasPrice          *big.Int
 	logger            *log.Logger
-	client            *ethclient.Client
+	client            *chain.Client
 	delegationAddress common.Address
 	delegation        *delegationmanager.ContractDelegationManager
+	txm               *txmgr.Manager
 }
 
 // New returns a new Eigen service
-func New(gasLimit uint64, gasPrice *big.Int, delegationAddress string, client *ethclient.Client, logger *log.Logger) (*Service, error) {
+func New(delegationAddress string, client *chain.Client, logger *log.Logger, txm *txmgr.Manager) (*Service, error) {
 	delegationContractAddress := common.HexToAddress(delegationAddress)
 	contractDelegation, err := delegationmanager.NewContractDelegationManager(delegationContractAddress, client)
 	if err != nil {
@@ -40,53 +41,34 @@ func New(gasLimit uint64, gasPrice *big.Int, delegationAddress string, client *e
 
 	return &Service{
 		chainID:           chainID,
-		gasLimit:          gasLimit,
-		gasPrice:          gasPrice,
 		logger:            logger,
 		client:            client,
 		delegationAddress: delegationContractAddress,
 		delegation:        contractDelegation,
+		txm:               txm,
 	}, nil
 }
 
-// RegisterAsOperator executes RegisterAsOperator smart contract function
-func (s *Service) RegisterAsOperator(pk *ecdsa.PrivateKey) error {
-	publicKey := pk.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return errors.New("Could not create public key from private key")
+// RegisterAsOperator registers account with the delegation manager with given details
+func (s *Service) RegisterAsOperator(account signer.Signer, details OperatorDetails) error {
+	if err := details.validate(); err != nil {
+		return err
 	}
-
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := s.client.PendingNonceAt(context.Background(), fromAddress)
-	if err != nil {
-		return errors.Wrap(err, "Error while getting nonce")
+	if err := s.checkMetadata(context.Background(), details); err != nil {
+		return err
 	}
+	opDetails := details.binding(account.Address())
 
-	transactor, err := bind.NewKeyedTransactorWithChainID(pk, s.chainID)
-	if err != nil {
-		return errors.Wrap(err, "Error while creating transactor")
-	}
-	transactor.Nonce = big.NewInt(int64(nonce))
-	transactor.Value = big.NewInt(0)
-	transactor.GasLimit = s.gasLimit
-	transactor.GasPrice = big.NewInt(21000)
-
-	opDetails := delegationmanager.IDelegationManagerOperatorDetails{
-		// DeprecatedEarningsReceiver: common.HexToAddress(operator.EarningsReceiverAddress),
-		// StakerOptOutWindowBlocks:   operator.StakerOptOutWindowBlocks,
-		// DelegationApprover: common.HexToAddress(operator.DelegationApproverAddress),
-	}
-
-	tx, err := s.delegation.RegisterAsOperator(transactor, opDetails, "")
+	result, err := s.txm.SendAs(context.Background(), account, s.chainID, "registerAsOperator", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.delegation.RegisterAsOperator(transactor, opDetails, details.MetadataURI)
+	})
 	if err != nil {
 		return errors.Wrap(err, "Error while registering as operator")
 	}
-
-	if err = s.client.SendTransaction(context.Background(), tx); err != nil {
-		return errors.Wrap(err, "Error while sending transaction")
+	if result.Status != txmgr.Mined {
+		return errors.Errorf("registerAsOperator transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
 	}
 
-	s.logger.Printf("Registered as operator, tx hash: %s\n", tx.Hash().Hex())
+	s.logger.Printf("Registered as operator, tx hash: %s\n", result.Tx.Hash().Hex())
 	return nil
 }
@@ -0,0 +1,499 @@
+package eigen
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	ierc20 "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IERC20"
+	istrategy "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IStrategy"
+	strategymanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/StrategyManager"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// DelegationApproval is the signature of an operator's delegation approver
+// allowing one staker to delegate to the operator
+type DelegationApproval struct {
+	Signature []byte
+	Salt      [32]byte
+	Expiry    *big.Int
+}
+
+// Withdrawal is a withdrawal queued in the delegation manager
+type Withdrawal struct {
+	Root       common.Hash
+	Withdrawal delegationmanager.IDelegationManagerWithdrawal
+}
+
+// StrategyManager returns the address of the strategy manager the delegation manager uses
+func (s *Service) StrategyManager(ctx context.Context) (common.Address, error) {
+	address, err := s.delegation.StrategyManager(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return address, errors.Wrap(err, "Error while getting strategy manager address")
+	}
+	return address, nil
+}
+
+// Approve lets spender transfer amount of token from account
+func (s *Service) Approve(ctx context.Context, account signer.Signer, token, spender common.Address, amount *big.Int) error {
+	erc20, err := ierc20.NewContractIERC20(token, s.client)
+	if err != nil {
+		return errors.Wrap(err, "Error while creating token")
+	}
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "approve", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return erc20.Approve(transactor, spender, amount)
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error while approving token")
+	}
+	if result.Status != txmgr.Mined {
+		return errors.Errorf("approve transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	s.logger.Printf("Approved %s to spend %s of token %s, tx hash: %s\n", spender.Hex(), amount, token.Hex(), result.Tx.Hash().Hex())
+	return nil
+}
+
+// Deposit deposits amount of the underlying token of strategy from account
+// through the strategy manager, approving the strategy manager first when
+// its allowance is too low. It returns the shares of account in strategy.
+func (s *Service) Deposit(ctx context.Context, account signer.Signer, strategy common.Address, amount *big.Int) (*big.Int, error) {
+	staker := account.Address()
+	managerAddress, err := s.StrategyManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := strategymanager.NewContractStrategyManager(managerAddress, s.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while creating strategy manager")
+	}
+	whitelisted, err := manager.StrategyIsWhitelistedForDeposit(&bind.CallOpts{Context: ctx}, strategy)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while checking strategy whitelist")
+	}
+	if !whitelisted {
+		return nil, errors.Errorf("Strategy %s is not whitelisted for deposits", strategy.Hex())
+	}
+
+	token, err := s.underlyingToken(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	erc20, err := ierc20.NewContractIERC20(token, s.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while creating token")
+	}
+	balance, err := erc20.BalanceOf(&bind.CallOpts{Context: ctx}, staker)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting token balance")
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, errors.Errorf("Balance of %s in token %s is %s, less than %s", staker.Hex(), token.Hex(), balance, amount)
+	}
+	allowance, err := erc20.Allowance(&bind.CallOpts{Context: ctx}, staker, managerAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting token allowance")
+	}
+	if allowance.Cmp(amount) < 0 {
+		if err := s.Approve(ctx, account, token, managerAddress, amount); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "depositIntoStrategy", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return manager.DepositIntoStrategy(transactor, strategy, token, amount)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while depositing into strategy")
+	}
+	if result.Status != txmgr.Mined {
+		return nil, errors.Errorf("depositIntoStrategy transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	shares, err := manager.StakerStrategyShares(&bind.CallOpts{Context: ctx}, staker, strategy)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting strategy shares")
+	}
+	s.logger.Printf("Deposited %s of token %s into strategy %s, %s now holds %s shares, tx hash: %s\n", amount, token.Hex(), strategy.Hex(), staker.Hex(), shares, result.Tx.Hash().Hex())
+	return shares, nil
+}
+
+// SignDelegationApproval signs the delegation manager digest allowing staker
+// to delegate to operator with approver, the delegation approver of operator.
+// The approval has a random salt and is valid for expiry after the latest block.
+func (s *Service) SignDelegationApproval(ctx context.Context, approver signer.Signer, staker, operator common.Address, expiry time.Duration) (DelegationApproval, error) {
+	details, err := s.OperatorDetails(ctx, operator)
+	if err != nil {
+		return DelegationApproval{}, err
+	}
+	if details.DelegationApprover != approver.Address() {
+		return DelegationApproval{}, errors.Errorf("Delegation approver of %s is %s, not %s", operator.Hex(), details.DelegationApprover.Hex(), approver.Address().Hex())
+	}
+
+	var salt [32]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return DelegationApproval{}, errors.Wrap(err, "Error while generating salt")
+	}
+	head, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return DelegationApproval{}, errors.Wrap(err, "Error while getting latest header")
+	}
+	expiresAt := new(big.Int).SetUint64(head.Time + uint64(expiry.Seconds()))
+
+	digest, err := s.delegation.CalculateDelegationApprovalDigestHash(&bind.CallOpts{Context: ctx}, staker, operator, approver.Address(), salt, expiresAt)
+	if err != nil {
+		return DelegationApproval{}, errors.Wrap(err, "Error while calculating delegation approval digest")
+	}
+	data := delegationApprovalTypedData(s.chainID, s.delegationAddress, approver.Address(), staker, operator, salt, expiresAt)
+	local, err := signer.TypedDataHash(data)
+	if err != nil {
+		return DelegationApproval{}, err
+	}
+	if local != common.Hash(digest) {
+		return DelegationApproval{}, errors.Errorf("Delegation approval digest %s does not match digest %s of the delegation manager", local.Hex(), common.Hash(digest).Hex())
+	}
+
+	sig, err := approver.SignTypedData(ctx, data)
+	if err != nil {
+		return DelegationApproval{}, errors.Wrap(err, "Error while signing delegation approval digest")
+	}
+	if err := signer.Verify(digest, sig, approver.Address()); err != nil {
+		return DelegationApproval{}, errors.Wrap(err, "Error while verifying delegation approval signature")
+	}
+
+	return DelegationApproval{
+		Signature: sig,
+		Salt:      salt,
+		Expiry:    expiresAt,
+	}, nil
+}
+
+// DelegateTo delegates the shares of account to operator. Operators with a
+// delegation approver require an approval, which is ignored otherwise.
+func (s *Service) DelegateTo(ctx context.Context, account signer.Signer, operator common.Address, approval *DelegationApproval) error {
+	staker := account.Address()
+	registered, err := s.IsOperator(ctx, operator)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return errors.Errorf("%s is not registered as operator", operator.Hex())
+	}
+	delegatedTo, err := s.delegation.DelegatedTo(&bind.CallOpts{Context: ctx}, staker)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting delegation of staker")
+	}
+	if delegatedTo == operator {
+		s.logger.Printf("Staker %s is already delegated to %s\n", staker.Hex(), operator.Hex())
+		return nil
+	}
+	if delegatedTo != (common.Address{}) {
+		return errors.Errorf("Staker %s is delegated to %s, undelegate first", staker.Hex(), delegatedTo.Hex())
+	}
+
+	details, err := s.OperatorDetails(ctx, operator)
+	if err != nil {
+		return err
+	}
+	signature := delegationmanager.ISignatureUtilsSignatureWithExpiry{Expiry: new(big.Int)}
+	var salt [32]byte
+	if details.DelegationApprover != (common.Address{}) {
+		if approval == nil {
+			return errors.Errorf("Operator %s requires a signature of its delegation approver %s", operator.Hex(), details.DelegationApprover.Hex())
+		}
+		signature = delegationmanager.ISignatureUtilsSignatureWithExpiry{Signature: approval.Signature, Expiry: approval.Expiry}
+		salt = approval.Salt
+	}
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "delegateTo", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.delegation.DelegateTo(transactor, operator, signature, salt)
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error while delegating to operator")
+	}
+	if result.Status != txmgr.Mined {
+		return errors.Errorf("delegateTo transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	if delegatedTo, err = s.delegation.DelegatedTo(&bind.CallOpts{Context: ctx}, staker); err != nil {
+		return errors.Wrap(err, "Error while getting delegation of staker")
+	}
+	if delegatedTo != operator {
+		return errors.Errorf("Staker %s is delegated to %s instead of %s", staker.Hex(), delegatedTo.Hex(), operator.Hex())
+	}
+	s.logger.Printf("Delegated %s to %s, tx hash: %s\n", staker.Hex(), operator.Hex(), result.Tx.Hash().Hex())
+	return nil
+}
+
+// Undelegate undelegates account from its operator, which queues withdrawals
+// of all its shares. It returns the queued withdrawals.
+func (s *Service) Undelegate(ctx context.Context, account signer.Signer) ([]Withdrawal, error) {
+	staker := account.Address()
+	delegatedTo, err := s.delegation.DelegatedTo(&bind.CallOpts{Context: ctx}, staker)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting delegation of staker")
+	}
+	if delegatedTo == (common.Address{}) {
+		return nil, errors.Errorf("Staker %s is not delegated", staker.Hex())
+	}
+
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "undelegate", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.delegation.Undelegate(transactor, staker)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while undelegating")
+	}
+	if result.Status != txmgr.Mined {
+		return nil, errors.Errorf("undelegate transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	s.logger.Printf("Undelegated %s from %s, tx hash: %s\n", staker.Hex(), delegatedTo.Hex(), result.Tx.Hash().Hex())
+	return s.queuedWithdrawals(result.Receipt), nil
+}
+
+// QueueWithdrawal queues a withdrawal of shares from strategies to account.
+// Without strategies every delegatable share is withdrawn, and without shares
+// all shares of the given strategies are. It returns the queued withdrawal.
+func (s *Service) QueueWithdrawal(ctx context.Context, account signer.Signer, strategies []common.Address, shares []*big.Int) ([]Withdrawal, error) {
+	staker := account.Address()
+	if len(shares) > 0 && len(shares) != len(strategies) {
+		return nil, errors.Errorf("Got %d share amounts for %d strategies", len(shares), len(strategies))
+	}
+	if len(shares) == 0 {
+		all, allShares, err := s.delegation.GetDelegatableShares(&bind.CallOpts{Context: ctx}, staker)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error while getting delegatable shares")
+		}
+		if len(strategies) == 0 {
+			strategies, shares = all, allShares
+		}
+		for _, strategy := range strategies[len(shares):] {
+			amount := new(big.Int)
+			for i := range all {
+				if all[i] == strategy {
+					amount = allShares[i]
+				}
+			}
+			if amount.Sign() == 0 {
+				return nil, errors.Errorf("Staker %s has no shares in strategy %s", staker.Hex(), strategy.Hex())
+			}
+			shares = append(shares, amount)
+		}
+	}
+	if len(strategies) == 0 {
+		return nil, errors.Errorf("Staker %s has no shares to withdraw", staker.Hex())
+	}
+
+	params := []delegationmanager.IDelegationManagerQueuedWithdrawalParams{{
+		Strategies: strategies,
+		Shares:     shares,
+		Withdrawer: staker,
+	}}
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "queueWithdrawals", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.delegation.QueueWithdrawals(transactor, params)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while queueing withdrawal")
+	}
+	if result.Status != txmgr.Mined {
+		return nil, errors.Errorf("queueWithdrawals transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	s.logger.Printf("Queued withdrawal of %s, tx hash: %s\n", staker.Hex(), result.Tx.Hash().Hex())
+	return s.queuedWithdrawals(result.Receipt), nil
+}
+
+// PendingWithdrawals returns the withdrawals of staker queued since
+// fromBlock that were not completed yet, reading logs in chunks of chunkSize
+func (s *Service) PendingWithdrawals(ctx context.Context, staker common.Address, fromBlock, chunkSize uint64) ([]Withdrawal, error) {
+	head, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting block number")
+	}
+	return s.pendingWithdrawals(ctx, staker, fromBlock, head, chunkSize)
+}
+
+// pendingWithdrawals returns the withdrawals of staker queued in blocks
+// [fromBlock, toBlock] that were not completed yet
+func (s *Service) pendingWithdrawals(ctx context.Context, staker common.Address, fromBlock, toBlock, chunkSize uint64) ([]Withdrawal, error) {
+	var withdrawals []Withdrawal
+	for start := fromBlock; start <= toBlock; start += chunkSize {
+		end := min(start+chunkSize-1, toBlock)
+		it, err := s.delegation.FilterWithdrawalQueued(&bind.FilterOpts{Start: start, End: &end, Context: ctx})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while filtering withdrawals from block %d to %d", start, end)
+		}
+		for it.Next() {
+			if it.Event.Withdrawal.Staker != staker {
+				continue
+			}
+			pending, err := s.delegation.PendingWithdrawals(&bind.CallOpts{Context: ctx}, it.Event.WithdrawalRoot)
+			if err != nil {
+				it.Close()
+				return nil, errors.Wrap(err, "Error while checking withdrawal")
+			}
+			if pending {
+				withdrawals = append(withdrawals, Withdrawal{Root: it.Event.WithdrawalRoot, Withdrawal: it.Event.Withdrawal})
+			}
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while reading withdrawals from block %d to %d", start, end)
+		}
+	}
+	return withdrawals, nil
+}
+
+// CompleteWithdrawals completes the withdrawals whose delay has passed,
+// either as tokens or as shares that are delegated again. It returns the
+// withdrawals that are not ready yet.
+func (s *Service) CompleteWithdrawals(ctx context.Context, account signer.Signer, withdrawals []Withdrawal, receiveAsTokens bool) ([]Withdrawal, error) {
+	head, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting block number")
+	}
+	beaconChainETH, err := s.delegation.BeaconChainETHStrategy(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting beacon chain ETH strategy")
+	}
+
+	ready, waiting, err := s.readyWithdrawals(ctx, withdrawals, head)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, withdrawal := range ready {
+		tokens, err := withdrawalTokens(withdrawal.Withdrawal, beaconChainETH, func(strategy common.Address) (common.Address, error) {
+			return s.underlyingToken(ctx, strategy)
+		})
+		if err != nil {
+			return waiting, err
+		}
+
+		w := withdrawal.Withdrawal
+		result, err := s.txm.SendAs(ctx, account, s.chainID, "completeQueuedWithdrawal", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+			return s.delegation.CompleteQueuedWithdrawal(transactor, w, tokens, new(big.Int), receiveAsTokens)
+		})
+		if err != nil {
+			return waiting, errors.Wrap(err, "Error while completing withdrawal")
+		}
+		if result.Status != txmgr.Mined {
+			return waiting, errors.Errorf("completeQueuedWithdrawal transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+		}
+		s.logger.Printf("Completed withdrawal %s, tx hash: %s\n", withdrawal.Root.Hex(), result.Tx.Hash().Hex())
+	}
+	return waiting, nil
+}
+
+// readyWithdrawals splits withdrawals into those whose delay has passed at
+// head and those that are still waiting
+func (s *Service) readyWithdrawals(ctx context.Context, withdrawals []Withdrawal, head uint64) (ready, waiting []Withdrawal, err error) {
+	for _, withdrawal := range withdrawals {
+		delay, err := s.delegation.GetWithdrawalDelay(&bind.CallOpts{Context: ctx}, withdrawal.Withdrawal.Strategies)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Error while getting withdrawal delay")
+		}
+		readyAt := uint64(withdrawal.Withdrawal.StartBlock) + delay.Uint64()
+		if head < readyAt {
+			s.logger.Printf("Withdrawal %s can be completed from block %d, head is %d\n", withdrawal.Root.Hex(), readyAt, head)
+			waiting = append(waiting, withdrawal)
+			continue
+		}
+		ready = append(ready, withdrawal)
+	}
+	return ready, waiting, nil
+}
+
+// withdrawalTokens returns the tokens completeQueuedWithdrawal expects for the
+// strategies of w, looked up with underlyingToken. Beacon chain ETH has no
+// token and gets the zero address.
+func withdrawalTokens(w delegationmanager.IDelegationManagerWithdrawal, beaconChainETH common.Address, underlyingToken func(common.Address) (common.Address, error)) ([]common.Address, error) {
+	tokens := make([]common.Address, len(w.Strategies))
+	for i, strategy := range w.Strategies {
+		if strategy == beaconChainETH {
+			continue
+		}
+		token, err := underlyingToken(strategy)
+		if err != nil {
+			return nil, err
+		}
+		tokens[i] = token
+	}
+	return tokens, nil
+}
+
+// queuedWithdrawals returns the withdrawals queued by the delegation manager in receipt
+func (s *Service) queuedWithdrawals(receipt *types.Receipt) []Withdrawal {
+	var withdrawals []Withdrawal
+	for _, log := range receipt.Logs {
+		if log.Address != s.delegationAddress {
+			continue
+		}
+		event, err := s.delegation.ParseWithdrawalQueued(*log)
+		if err != nil {
+			continue
+		}
+		withdrawals = append(withdrawals, Withdrawal{Root: event.WithdrawalRoot, Withdrawal: event.Withdrawal})
+		s.logger.Printf("Queued withdrawal %s at block %d\n", common.Hash(event.WithdrawalRoot).Hex(), event.Withdrawal.StartBlock)
+	}
+	return withdrawals
+}
+
+// underlyingToken returns the token deposited into strategy
+func (s *Service) underlyingToken(ctx context.Context, strategy common.Address) (common.Address, error) {
+	contract, err := istrategy.NewContractIStrategy(strategy, s.client)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "Error while creating strategy")
+	}
+	token, err := contract.UnderlyingToken(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "Error while getting underlying token of strategy %s", strategy.Hex())
+	}
+	return token, nil
+}
+
+// delegationApprovalTypedData is the DelegationApproval message of the delegation manager
+func delegationApprovalTypedData(chainID *big.Int, delegation, approver, staker, operator common.Address, salt [32]byte, expiry *big.Int) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"DelegationApproval": {
+				{Name: "delegationApprover", Type: "address"},
+				{Name: "staker", Type: "address"},
+				{Name: "operator", Type: "address"},
+				{Name: "salt", Type: "bytes32"},
+				{Name: "expiry", Type: "uint256"},
+			},
+		},
+		PrimaryType: "DelegationApproval",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "EigenLayer",
+			ChainId:           (*math.HexOrDecimal256)(chainID),
+			VerifyingContract: delegation.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"delegationApprover": approver.Hex(),
+			"staker":             staker.Hex(),
+			"operator":           operator.Hex(),
+			"salt":               hexutil.Encode(salt[:]),
+			"expiry":             expiry.String(),
+		},
+	}
+}
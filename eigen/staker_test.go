@@ -0,0 +1,220 @@
+package eigen
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"slices"
+	"testing"
+
+	delegationmanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/DelegationManager"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// delegationApprovalDigest computes DelegationManager.calculateDelegationApprovalDigestHash
+// the way the contract does
+func delegationApprovalDigest(chainID *big.Int, delegation, approver, staker, operator common.Address, salt [32]byte, expiry *big.Int) common.Hash {
+	word := func(b []byte) []byte { return common.LeftPadBytes(b, 32) }
+	domainSeparator := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,uint256 chainId,address verifyingContract)")),
+		crypto.Keccak256([]byte("EigenLayer")),
+		word(chainID.Bytes()),
+		word(delegation.Bytes()),
+	)
+	structHash := crypto.Keccak256(
+		crypto.Keccak256([]byte("DelegationApproval(address delegationApprover,address staker,address operator,bytes32 salt,uint256 expiry)")),
+		word(approver.Bytes()),
+		word(staker.Bytes()),
+		word(operator.Bytes()),
+		salt[:],
+		word(expiry.Bytes()),
+	)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator, structHash)
+}
+
+func TestDelegationApprovalDigest(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	approver := signer.NewLocal(pk)
+	chainID := big.NewInt(17000)
+	delegation := common.HexToAddress("0xA44151489861Fe9e3055d95adC98FbD462B948e7")
+	staker, operator := common.HexToAddress("0xa1"), common.HexToAddress("0x0b")
+	salt := [32]byte{1, 2, 3}
+	expiry := big.NewInt(1700000000)
+
+	data := delegationApprovalTypedData(chainID, delegation, approver.Address(), staker, operator, salt, expiry)
+	got, err := signer.TypedDataHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := delegationApprovalDigest(chainID, delegation, approver.Address(), staker, operator, salt, expiry)
+	if got != want {
+		t.Fatalf("digest = %s, want %s", got.Hex(), want.Hex())
+	}
+
+	// The approval is accepted when the approver signed exactly this digest
+	sig, err := approver.SignTypedData(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Verify(want, sig, approver.Address()); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	other := delegationApprovalDigest(chainID, delegation, approver.Address(), common.HexToAddress("0xa2"), operator, salt, expiry)
+	if err := signer.Verify(other, sig, approver.Address()); err == nil {
+		t.Fatal("approval for one staker verified for another")
+	}
+}
+
+// withdrawalQueuedLog returns a WithdrawalQueued log of the delegation manager at block
+func withdrawalQueuedLog(t *testing.T, block uint64, root common.Hash, withdrawal delegationmanager.IDelegationManagerWithdrawal) types.Log {
+	t.Helper()
+	parsed, err := delegationmanager.ContractDelegationManagerMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := parsed.Events["WithdrawalQueued"]
+	data, err := event.Inputs.NonIndexed().Pack(root, withdrawal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return types.Log{Address: common.HexToAddress("0xde"), Topics: []common.Hash{event.ID}, Data: data, BlockNumber: block}
+}
+
+func TestPendingWithdrawals(t *testing.T) {
+	staker, other := common.HexToAddress("0xa1"), common.HexToAddress("0xa2")
+	withdrawal := func(staker common.Address, start uint32) delegationmanager.IDelegationManagerWithdrawal {
+		return delegationmanager.IDelegationManagerWithdrawal{
+			Staker:     staker,
+			Withdrawer: staker,
+			Nonce:      new(big.Int),
+			StartBlock: start,
+			Strategies: []common.Address{common.HexToAddress("0x51")},
+			Shares:     []*big.Int{big.NewInt(10)},
+		}
+	}
+	pending := common.HexToHash("0x01")
+	completed := common.HexToHash("0x02")
+	otherStaker := common.HexToHash("0x03")
+	late := common.HexToHash("0x04")
+
+	backend := newCallBackend(t, delegationmanager.ContractDelegationManagerMetaData)
+	backend.logs = []types.Log{
+		withdrawalQueuedLog(t, 2, pending, withdrawal(staker, 2)),
+		withdrawalQueuedLog(t, 5, completed, withdrawal(staker, 5)),
+		withdrawalQueuedLog(t, 6, otherStaker, withdrawal(other, 6)),
+		withdrawalQueuedLog(t, 9, late, withdrawal(staker, 9)),
+	}
+	backend.handle("pendingWithdrawals", func(_ common.Address, args []interface{}) []interface{} {
+		root := common.Hash(args[0].([32]byte))
+		return []interface{}{root != completed}
+	})
+	s := newStakerService(t, backend)
+
+	withdrawals, err := s.pendingWithdrawals(context.Background(), staker, 1, 9, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roots []common.Hash
+	for _, w := range withdrawals {
+		roots = append(roots, w.Root)
+	}
+	if !slices.Equal(roots, []common.Hash{pending, late}) {
+		t.Fatalf("pending withdrawals = %v, want %v", roots, []common.Hash{pending, late})
+	}
+	if withdrawals[0].Withdrawal.StartBlock != 2 || withdrawals[0].Withdrawal.Shares[0].Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("withdrawal = %+v, want the queued one", withdrawals[0].Withdrawal)
+	}
+
+	// Logs are read in chunks of 4 blocks
+	var ranges [][2]uint64
+	for _, query := range backend.queries {
+		ranges = append(ranges, [2]uint64{query.FromBlock.Uint64(), query.ToBlock.Uint64()})
+	}
+	if want := [][2]uint64{{1, 4}, {5, 8}, {9, 9}}; !slices.Equal(ranges, want) {
+		t.Fatalf("queried ranges = %v, want %v", ranges, want)
+	}
+}
+
+func TestReadyWithdrawals(t *testing.T) {
+	fast, slow := common.HexToAddress("0x51"), common.HexToAddress("0x52")
+	backend := newCallBackend(t, delegationmanager.ContractDelegationManagerMetaData)
+	backend.handle("getWithdrawalDelay", func(_ common.Address, args []interface{}) []interface{} {
+		if slices.Contains(args[0].([]common.Address), slow) {
+			return []interface{}{big.NewInt(50)}
+		}
+		return []interface{}{big.NewInt(10)}
+	})
+	s := newStakerService(t, backend)
+
+	withdrawal := func(root byte, start uint32, strategies ...common.Address) Withdrawal {
+		return Withdrawal{Root: common.Hash{root}, Withdrawal: delegationmanager.IDelegationManagerWithdrawal{StartBlock: start, Strategies: strategies}}
+	}
+	withdrawals := []Withdrawal{
+		withdrawal(1, 90, fast),
+		withdrawal(2, 91, fast),
+		withdrawal(3, 60, fast, slow),
+		withdrawal(4, 40, slow),
+	}
+
+	ready, waiting, err := s.readyWithdrawals(context.Background(), withdrawals, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := func(withdrawals []Withdrawal) []byte {
+		var roots []byte
+		for _, w := range withdrawals {
+			roots = append(roots, w.Root[0])
+		}
+		return roots
+	}
+	if !slices.Equal(roots(ready), []byte{1, 4}) || !slices.Equal(roots(waiting), []byte{2, 3}) {
+		t.Fatalf("ready %v, waiting %v, want ready [1 4] and waiting [2 3]", roots(ready), roots(waiting))
+	}
+}
+
+func TestWithdrawalTokens(t *testing.T) {
+	beaconChainETH := common.HexToAddress("0xbeac02")
+	strategy, token := common.HexToAddress("0x51"), common.HexToAddress("0x70")
+	underlying := func(s common.Address) (common.Address, error) {
+		if s != strategy {
+			return common.Address{}, errors.Errorf("unknown strategy %s", s.Hex())
+		}
+		return token, nil
+	}
+
+	tokens, err := withdrawalTokens(delegationmanager.IDelegationManagerWithdrawal{Strategies: []common.Address{strategy, beaconChainETH}}, beaconChainETH, underlying)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tokens, []common.Address{token, {}}) {
+		t.Fatalf("tokens = %v, want the underlying token and the zero address for beacon chain ETH", tokens)
+	}
+
+	if _, err := withdrawalTokens(delegationmanager.IDelegationManagerWithdrawal{Strategies: []common.Address{common.HexToAddress("0x53")}}, beaconChainETH, underlying); err == nil {
+		t.Fatal("withdrawalTokens ignored a strategy without token")
+	}
+}
+
+// newStakerService returns a Service reading the delegation manager from backend
+func newStakerService(t *testing.T, backend *callBackend) *Service {
+	t.Helper()
+	delegation, err := delegationmanager.NewContractDelegationManager(common.HexToAddress("0xde"), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Service{
+		chainID:           big.NewInt(17000),
+		logger:            log.New(io.Discard, "", 0),
+		delegationAddress: common.HexToAddress("0xde"),
+		delegation:        delegation,
+	}
+}
@@ -0,0 +1,82 @@
+package eigen
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// RegisterWithStakeRegistry registers account in the stake registry of the AVS
+// with signingKey, the registry registers it with the AVS in turn. Membership,
+// AVS registration and signing key are checked on-chain afterwards. It
+// returns the operator weight in the registry.
+func (s *Service) RegisterWithStakeRegistry(ctx context.Context, account signer.Signer, avs *AVS, signingKey common.Address, expiry time.Duration) (*big.Int, error) {
+	operator := account.Address()
+	if signingKey == (common.Address{}) {
+		signingKey = operator
+	}
+
+	operatorSignature, err := s.operatorSignature(ctx, account, avs, expiry)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.txm.SendAs(ctx, account, s.chainID, "registerOperatorWithSignature", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return avs.registry.RegisterOperatorWithSignature(transactor, stakeregistry.ISignatureUtilsSignatureWithSaltAndExpiry(operatorSignature), signingKey)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while registering operator in stake registry")
+	}
+	if result.Status != txmgr.Mined {
+		return nil, errors.Errorf("registerOperatorWithSignature transaction %s is %s: %s", result.Tx.Hash().Hex(), result.Status, result.Reason)
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	member, err := avs.registry.OperatorRegistered(opts, operator)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while checking stake registry membership")
+	}
+	if !member {
+		return nil, errors.Errorf("Operator %s is not a member of the stake registry after transaction %s", operator.Hex(), result.Tx.Hash().Hex())
+	}
+	if err := avs.checkRegistered(ctx, operator, operatorSignature.Salt); err != nil {
+		return nil, errors.Wrapf(err, "Error after transaction %s", result.Tx.Hash().Hex())
+	}
+	registeredKey, err := avs.registry.GetLastestOperatorSigningKey(opts, operator)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting operator signing key")
+	}
+	if registeredKey != signingKey {
+		return nil, errors.Errorf("Stake registry has signing key %s instead of %s", registeredKey.Hex(), signingKey.Hex())
+	}
+
+	weight, err := s.OperatorWeight(ctx, operator, avs)
+	if err != nil {
+		return nil, err
+	}
+	minimum, err := avs.registry.MinimumWeight(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting minimum weight")
+	}
+	s.logger.Printf("Registered operator %s in stake registry with signing key %s and weight %s (minimum %s), tx hash: %s\n",
+		operator.Hex(), signingKey.Hex(), weight, minimum, result.Tx.Hash().Hex())
+	return weight, nil
+}
+
+// OperatorWeight returns the weight of operator at the last checkpoint of the
+// stake registry, which responses are checked against
+func (s *Service) OperatorWeight(ctx context.Context, operator common.Address, avs *AVS) (*big.Int, error) {
+	weight, err := avs.registry.GetLastCheckpointOperatorWeight(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting operator weight")
+	}
+	return weight, nil
+}
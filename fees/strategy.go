@@ -0,0 +1,144 @@
+package fees
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LegacyFixed sends legacy transactions with a configured gas price
+	LegacyFixed = "legacy-fixed"
+	// LegacyOracle sends legacy transactions with the gas price suggested by the node
+	LegacyOracle = "legacy-oracle"
+	// EIP1559 sends dynamic fee transactions based on the latest base fee
+	EIP1559 = "eip1559"
+)
+
+// Backend is the part of the Ethereum client fee strategies need
+type Backend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// Strategy sets the fee fields of a transactor before a transaction is built
+type Strategy interface {
+	Apply(ctx context.Context, opts *bind.TransactOpts) error
+}
+
+// Config selects and configures a Strategy
+type Config struct {
+	Strategy string
+	// GasPrice is the price used by the legacy-fixed strategy
+	GasPrice *big.Int
+	// MaxFee caps the gas price, or the fee cap of dynamic fee transactions, nil means no cap
+	MaxFee *big.Int
+	// PriorityFee is the tip of dynamic fee transactions, nil uses the node's suggestion
+	PriorityFee *big.Int
+	// BaseFeeMultiplier is how many times the latest base fee the fee cap covers
+	BaseFeeMultiplier int64
+}
+
+// New returns the Strategy selected by cfg
+func New(cfg Config, backend Backend) (Strategy, error) {
+	switch cfg.Strategy {
+	case LegacyFixed:
+		if cfg.GasPrice == nil || cfg.GasPrice.Sign() <= 0 {
+			return nil, errors.New("GAS_PRICE must be set for the legacy-fixed fee strategy")
+		}
+		return &fixed{gasPrice: cfg.GasPrice}, nil
+	case LegacyOracle:
+		return &oracle{backend: backend, maxFee: cfg.MaxFee}, nil
+	case EIP1559:
+		if cfg.BaseFeeMultiplier <= 0 {
+			return nil, errors.New("Base fee multiplier must be greater than 0")
+		}
+		return &dynamic{
+			backend:     backend,
+			maxFee:      cfg.MaxFee,
+			priorityFee: cfg.PriorityFee,
+			multiplier:  big.NewInt(cfg.BaseFeeMultiplier),
+		}, nil
+	default:
+		return nil, errors.Errorf("Unknown fee strategy %q", cfg.Strategy)
+	}
+}
+
+// fixed uses the same gas price for every transaction
+type fixed struct {
+	gasPrice *big.Int
+}
+
+func (f *fixed) Apply(ctx context.Context, opts *bind.TransactOpts) error {
+	setLegacy(opts, f.gasPrice)
+	return nil
+}
+
+// oracle uses the gas price suggested by the node
+type oracle struct {
+	backend Backend
+	maxFee  *big.Int
+}
+
+func (o *oracle) Apply(ctx context.Context, opts *bind.TransactOpts) error {
+	gasPrice, err := o.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting gas price")
+	}
+	setLegacy(opts, capped(gasPrice, o.maxFee))
+	return nil
+}
+
+// dynamic sets the fee cap to a multiple of the latest base fee plus the tip
+type dynamic struct {
+	backend     Backend
+	maxFee      *big.Int
+	priorityFee *big.Int
+	multiplier  *big.Int
+}
+
+func (d *dynamic) Apply(ctx context.Context, opts *bind.TransactOpts) error {
+	head, err := d.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting latest header")
+	}
+	if head.BaseFee == nil {
+		return errors.New("Chain does not support EIP-1559, use a legacy fee strategy")
+	}
+
+	tip := d.priorityFee
+	if tip == nil {
+		if tip, err = d.backend.SuggestGasTipCap(ctx); err != nil {
+			return errors.Wrap(err, "Error while getting gas tip cap")
+		}
+	}
+
+	feeCap := new(big.Int).Mul(head.BaseFee, d.multiplier)
+	feeCap = capped(feeCap.Add(feeCap, tip), d.maxFee)
+	if tip.Cmp(feeCap) > 0 {
+		tip = feeCap
+	}
+
+	opts.GasPrice = nil
+	opts.GasFeeCap = feeCap
+	opts.GasTipCap = new(big.Int).Set(tip)
+	return nil
+}
+
+// setLegacy sets the gas price and clears dynamic fee fields left by an earlier attempt
+func setLegacy(opts *bind.TransactOpts, gasPrice *big.Int) {
+	opts.GasPrice = new(big.Int).Set(gasPrice)
+	opts.GasFeeCap = nil
+	opts.GasTipCap = nil
+}
+
+func capped(fee, max *big.Int) *big.Int {
+	if max != nil && max.Sign() > 0 && fee.Cmp(max) > 0 {
+		return new(big.Int).Set(max)
+	}
+	return fee
+}
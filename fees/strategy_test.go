@@ -0,0 +1,160 @@
+package fees
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testBackend struct {
+	baseFee  *big.Int
+	gasPrice *big.Int
+	tip      *big.Int
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: b.baseFee}, nil
+}
+
+func (b *testBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return b.gasPrice, nil
+}
+
+func (b *testBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return b.tip, nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "legacy fixed", cfg: Config{Strategy: LegacyFixed, GasPrice: big.NewInt(1)}},
+		{name: "legacy fixed without price", cfg: Config{Strategy: LegacyFixed}, wantErr: true},
+		{name: "legacy oracle", cfg: Config{Strategy: LegacyOracle}},
+		{name: "eip1559", cfg: Config{Strategy: EIP1559, BaseFeeMultiplier: 2}},
+		{name: "eip1559 without multiplier", cfg: Config{Strategy: EIP1559}, wantErr: true},
+		{name: "unknown", cfg: Config{Strategy: "cheap"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg, &testBackend{}); (err != nil) != tt.wantErr {
+				t.Fatalf("New error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e9)) }
+	tests := []struct {
+		name       string
+		cfg        Config
+		backend    *testBackend
+		wantPrice  *big.Int
+		wantFeeCap *big.Int
+		wantTipCap *big.Int
+		wantErr    bool
+	}{
+		{
+			name:      "legacy fixed",
+			cfg:       Config{Strategy: LegacyFixed, GasPrice: gwei(5)},
+			backend:   &testBackend{},
+			wantPrice: gwei(5),
+		},
+		{
+			name:      "legacy oracle",
+			cfg:       Config{Strategy: LegacyOracle},
+			backend:   &testBackend{gasPrice: gwei(7)},
+			wantPrice: gwei(7),
+		},
+		{
+			name:      "legacy oracle capped",
+			cfg:       Config{Strategy: LegacyOracle, MaxFee: gwei(6)},
+			backend:   &testBackend{gasPrice: gwei(7)},
+			wantPrice: gwei(6),
+		},
+		{
+			name:       "eip1559 suggested tip",
+			cfg:        Config{Strategy: EIP1559, BaseFeeMultiplier: 2},
+			backend:    &testBackend{baseFee: gwei(10), tip: gwei(1)},
+			wantFeeCap: gwei(21),
+			wantTipCap: gwei(1),
+		},
+		{
+			name:       "eip1559 configured tip",
+			cfg:        Config{Strategy: EIP1559, BaseFeeMultiplier: 3, PriorityFee: gwei(2)},
+			backend:    &testBackend{baseFee: gwei(10), tip: gwei(1)},
+			wantFeeCap: gwei(32),
+			wantTipCap: gwei(2),
+		},
+		{
+			name:       "eip1559 capped",
+			cfg:        Config{Strategy: EIP1559, BaseFeeMultiplier: 2, MaxFee: gwei(15)},
+			backend:    &testBackend{baseFee: gwei(10), tip: gwei(1)},
+			wantFeeCap: gwei(15),
+			wantTipCap: gwei(1),
+		},
+		{
+			name:       "eip1559 tip above cap",
+			cfg:        Config{Strategy: EIP1559, BaseFeeMultiplier: 2, MaxFee: gwei(3), PriorityFee: gwei(5)},
+			backend:    &testBackend{baseFee: gwei(10)},
+			wantFeeCap: gwei(3),
+			wantTipCap: gwei(3),
+		},
+		{
+			name:    "eip1559 without base fee",
+			cfg:     Config{Strategy: EIP1559, BaseFeeMultiplier: 2},
+			backend: &testBackend{tip: gwei(1)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := New(tt.cfg, tt.backend)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Fields left by an earlier attempt with another strategy are replaced
+			opts := &bind.TransactOpts{GasPrice: big.NewInt(1), GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)}
+			err = strategy.Apply(context.Background(), opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !equal(opts.GasPrice, tt.wantPrice) || !equal(opts.GasFeeCap, tt.wantFeeCap) || !equal(opts.GasTipCap, tt.wantTipCap) {
+				t.Fatalf("Apply = price %v, fee cap %v, tip %v, want %v, %v, %v", opts.GasPrice, opts.GasFeeCap, opts.GasTipCap, tt.wantPrice, tt.wantFeeCap, tt.wantTipCap)
+			}
+		})
+	}
+}
+
+// Applying a strategy never changes the configured values
+func TestApplyCopies(t *testing.T) {
+	gasPrice := big.NewInt(5)
+	strategy, err := New(Config{Strategy: LegacyFixed, GasPrice: gasPrice}, &testBackend{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &bind.TransactOpts{}
+	if err := strategy.Apply(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	opts.GasPrice.Add(opts.GasPrice, big.NewInt(1))
+	if gasPrice.Int64() != 5 {
+		t.Fatalf("configured gas price changed to %v", gasPrice)
+	}
+}
+
+func equal(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Cmp(b) == 0
+}
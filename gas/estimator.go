@@ -0,0 +1,139 @@
+package gas
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// Backend is the part of the Ethereum client the Estimator needs
+type Backend interface {
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+}
+
+// Config configures the Estimator
+type Config struct {
+	// Multiplier is applied to every estimate as a safety margin
+	Multiplier float64
+	// Ceilings caps the gas limit per contract method name
+	Ceilings map[string]uint64
+	// Fallback is the gas limit used when estimation fails, zero fails the transaction instead
+	Fallback uint64
+}
+
+// Estimator sets the gas limit of a transaction from an EstimateGas call
+type Estimator struct {
+	backend Backend
+	logger  *log.Logger
+	cfg     Config
+
+	mu      sync.RWMutex
+	methods map[[4]byte]string
+}
+
+// New returns a new gas Estimator
+func New(backend Backend, logger *log.Logger, cfg Config) *Estimator {
+	return &Estimator{
+		backend: backend,
+		logger:  logger,
+		cfg:     cfg,
+		methods: make(map[[4]byte]string),
+	}
+}
+
+// Register makes methods of the contract known by name, so their ceilings apply
+func (e *Estimator) Register(metadata *bind.MetaData) error {
+	parsed, err := metadata.GetAbi()
+	if err != nil {
+		return errors.Wrap(err, "Error while parsing contract abi")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, method := range parsed.Methods {
+		var selector [4]byte
+		copy(selector[:], method.ID)
+		e.methods[selector] = name
+	}
+	return nil
+}
+
+// Apply builds the transaction with fn without sending it, estimates its gas
+// and sets opts.GasLimit to the estimate with the safety margin, capped by
+// the ceiling of the called method
+func (e *Estimator) Apply(ctx context.Context, opts *bind.TransactOpts, fn func(*bind.TransactOpts) (*types.Transaction, error)) error {
+	dry := *opts
+	dry.NoSend = true
+	// Any non-zero limit stops bind from estimating on its own
+	dry.GasLimit = 1
+	// The dry run is never sent, so a remote signer is not asked to sign it
+	dry.Signer = unsigned
+	tx, err := fn(&dry)
+	if err != nil {
+		return errors.Wrap(err, "Error while building transaction")
+	}
+	method := e.method(tx.Data())
+
+	estimate, err := e.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:      opts.From,
+		To:        tx.To(),
+		GasPrice:  opts.GasPrice,
+		GasFeeCap: opts.GasFeeCap,
+		GasTipCap: opts.GasTipCap,
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	})
+	if err != nil {
+		if e.cfg.Fallback == 0 {
+			return errors.Wrapf(err, "Error while estimating gas of %s", method)
+		}
+		e.logger.Printf("Error while estimating gas of %s, using fallback limit %d: %v\n", method, e.cfg.Fallback, err)
+		opts.GasLimit = e.cfg.Fallback
+		return nil
+	}
+
+	limit := uint64(float64(estimate) * e.cfg.Multiplier)
+	if limit < estimate {
+		limit = estimate
+	}
+	if ceiling, ok := e.cfg.Ceilings[method]; ok {
+		if estimate > ceiling {
+			return errors.Errorf("Estimated gas %d of %s exceeds ceiling %d", estimate, method, ceiling)
+		}
+		if limit > ceiling {
+			limit = ceiling
+		}
+	}
+
+	opts.GasLimit = limit
+	return nil
+}
+
+// unsigned is a bind.SignerFn returning the transaction as it is
+func unsigned(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	return tx, nil
+}
+
+// method returns the name of the called method, or the selector when it is not registered
+func (e *Estimator) method(data []byte) string {
+	if len(data) < 4 {
+		return "transfer"
+	}
+
+	var selector [4]byte
+	copy(selector[:], data[:4])
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if name, ok := e.methods[selector]; ok {
+		return name
+	}
+	return hexutil.Encode(selector[:])
+}
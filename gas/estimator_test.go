@@ -0,0 +1,117 @@
+package gas
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+type testBackend struct {
+	estimate uint64
+	err      error
+	calls    []ethereum.CallMsg
+}
+
+func (b *testBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	b.calls = append(b.calls, call)
+	return b.estimate, b.err
+}
+
+var testMetaData = &bind.MetaData{
+	ABI: `[{"type":"function","name":"respondToTask","inputs":[],"outputs":[],"stateMutability":"nonpayable"}]`,
+}
+
+// call returns a transaction builder calling method, and records whether it was sent
+func call(method string, sent *bool) func(*bind.TransactOpts) (*types.Transaction, error) {
+	return func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		if !opts.NoSend {
+			*sent = true
+		}
+		to := common.HexToAddress("0x01")
+		return opts.Signer(opts.From, types.NewTx(&types.LegacyTx{To: &to, Gas: opts.GasLimit, Data: crypto.Keccak256([]byte(method + "()"))[:4]}))
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		method   string
+		estimate uint64
+		err      error
+		want     uint64
+		wantErr  bool
+	}{
+		{name: "multiplier", cfg: Config{Multiplier: 1.2}, method: "respondToTask", estimate: 100000, want: 120000},
+		{name: "multiplier below 1", cfg: Config{Multiplier: 0.5}, method: "respondToTask", estimate: 100000, want: 100000},
+		{name: "capped by ceiling", cfg: Config{Multiplier: 1.5, Ceilings: map[string]uint64{"respondToTask": 120000}}, method: "respondToTask", estimate: 100000, want: 120000},
+		{name: "below ceiling", cfg: Config{Multiplier: 1.1, Ceilings: map[string]uint64{"respondToTask": 200000}}, method: "respondToTask", estimate: 100000, want: 110000},
+		{name: "estimate above ceiling", cfg: Config{Multiplier: 1, Ceilings: map[string]uint64{"respondToTask": 50000}}, method: "respondToTask", estimate: 100000, wantErr: true},
+		{name: "ceiling of another method", cfg: Config{Multiplier: 1, Ceilings: map[string]uint64{"respondToTask": 50000}}, method: "createNewTask", estimate: 100000, want: 100000},
+		{name: "fallback", cfg: Config{Multiplier: 1.2, Fallback: 300000}, method: "respondToTask", err: errors.New("execution reverted"), want: 300000},
+		{name: "no fallback", cfg: Config{Multiplier: 1.2}, method: "respondToTask", err: errors.New("execution reverted"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &testBackend{estimate: tt.estimate, err: tt.err}
+			estimator := New(backend, log.New(io.Discard, "", 0), tt.cfg)
+			if err := estimator.Register(testMetaData); err != nil {
+				t.Fatal(err)
+			}
+
+			sent, signed := false, false
+			opts := &bind.TransactOpts{From: common.HexToAddress("0x02"), GasPrice: big.NewInt(1), Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				signed = true
+				return tx, nil
+			}}
+			err := estimator.Apply(context.Background(), opts, call(tt.method, &sent))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply error = %v, want error %v", err, tt.wantErr)
+			}
+			if sent {
+				t.Fatal("Apply sent the transaction")
+			}
+			if signed {
+				t.Fatal("Apply signed the dry run with the caller's signer")
+			}
+			if opts.NoSend {
+				t.Fatal("Apply changed NoSend of the caller's transactor")
+			}
+			if !tt.wantErr && opts.GasLimit != tt.want {
+				t.Fatalf("GasLimit = %d, want %d", opts.GasLimit, tt.want)
+			}
+			if len(backend.calls) != 1 || backend.calls[0].From != opts.From {
+				t.Fatalf("EstimateGas calls = %+v, want one from %s", backend.calls, opts.From.Hex())
+			}
+		})
+	}
+}
+
+func TestMethod(t *testing.T) {
+	estimator := New(&testBackend{}, log.New(io.Discard, "", 0), Config{})
+	if err := estimator.Register(testMetaData); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{data: nil, want: "transfer"},
+		{data: crypto.Keccak256([]byte("respondToTask()"))[:4], want: "respondToTask"},
+		{data: []byte{0xde, 0xad, 0xbe, 0xef, 0x01}, want: "0xdeadbeef"},
+	}
+	for _, tt := range tests {
+		if got := estimator.method(tt.data); got != tt.want {
+			t.Fatalf("method(%x) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
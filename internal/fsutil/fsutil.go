@@ -0,0 +1,36 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// WriteFileAtomic replaces the file at path with data. It writes to a
+// temporary file in the same directory first and renames it over path, so a
+// crash never leaves a truncated file behind.
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return errors.Wrap(err, "Error while creating temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "Error while writing temporary file")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "Error while syncing temporary file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "Error while closing temporary file")
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "Error while replacing file")
+	}
+	return nil
+}
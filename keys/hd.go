@@ -0,0 +1,120 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultHDPath is the BIP-44 path of Ethereum accounts, the wallet index is appended to it
+var DefaultHDPath = accounts.DefaultRootDerivationPath
+
+// NewMnemonic generates a new 24 word BIP-39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", errors.Wrap(err, "Error while generating entropy")
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", errors.Wrap(err, "Error while generating mnemonic")
+	}
+	return mnemonic, nil
+}
+
+// ReadMnemonic returns the mnemonic stored in file
+func ReadMnemonic(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", errors.Wrap(err, "Error while reading mnemonic file")
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
+
+// HDPath returns the path of the wallet at index below base
+func HDPath(base accounts.DerivationPath, index uint32) (accounts.DerivationPath, error) {
+	if index >= 0x80000000 {
+		return nil, errors.Errorf("Wallet index %d is out of range", index)
+	}
+	path := make(accounts.DerivationPath, len(base), len(base)+1)
+	copy(path, base)
+	return append(path, index), nil
+}
+
+// Derive returns the key at path of the BIP-32 wallet seeded by mnemonic
+func Derive(mnemonic string, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while parsing mnemonic")
+	}
+
+	key, _, err := derive(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
+
+// derive returns the private key and chain code at path of the BIP-32 wallet seeded by seed
+func derive(seed []byte, path accounts.DerivationPath) (*big.Int, []byte, error) {
+	key, chainCode, err := childKey([]byte("Bitcoin seed"), seed)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error while deriving master key")
+	}
+	for _, index := range path {
+		if key, chainCode, err = deriveChild(key, chainCode, index); err != nil {
+			return nil, nil, errors.Wrapf(err, "Error while deriving %s", path)
+		}
+	}
+	return key, chainCode, nil
+}
+
+// deriveChild is the BIP-32 private parent key to private child key derivation
+func deriveChild(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	data := make([]byte, 0, 37)
+	if index >= 0x80000000 {
+		data = append(data, 0)
+		data = append(data, math.PaddedBigBytes(key, 32)...)
+	} else {
+		pk, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&pk.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	tweak, childChainCode, err := childKey(chainCode, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	child := new(big.Int).Add(tweak, key)
+	child.Mod(child, crypto.S256().Params().N)
+	if child.Sign() == 0 {
+		return nil, nil, errors.Errorf("Invalid child key at index %d", index)
+	}
+	return child, childChainCode, nil
+}
+
+// childKey splits HMAC-SHA512(hmacKey, data) into a key and a chain code
+func childKey(hmacKey, data []byte) (*big.Int, []byte, error) {
+	mac := hmac.New(sha512.New, hmacKey)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, nil, errors.New("Invalid derived key")
+	}
+	return key, sum[32:], nil
+}
@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Test vectors 1 and 3 of the BIP-32 specification, vector 3 covers private
+// keys with leading zeros
+func TestDeriveBIP32Vectors(t *testing.T) {
+	tests := []struct {
+		seed      string
+		path      string
+		chainCode string
+		key       string
+	}{
+		{
+			seed:      "000102030405060708090a0b0c0d0e0f",
+			path:      "m",
+			chainCode: "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+			key:       "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+		},
+		{
+			seed:      "000102030405060708090a0b0c0d0e0f",
+			path:      "m/0'",
+			chainCode: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+			key:       "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+		},
+		{
+			seed:      "000102030405060708090a0b0c0d0e0f",
+			path:      "m/0'/1",
+			chainCode: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+			key:       "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+		},
+		{
+			seed:      "000102030405060708090a0b0c0d0e0f",
+			path:      "m/0'/1/2'",
+			chainCode: "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f",
+			key:       "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+		},
+		{
+			seed:      "000102030405060708090a0b0c0d0e0f",
+			path:      "m/0'/1/2'/2",
+			chainCode: "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd",
+			key:       "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+		},
+		{
+			seed:      "000102030405060708090a0b0c0d0e0f",
+			path:      "m/0'/1/2'/2/1000000000",
+			chainCode: "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+			key:       "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		},
+		{
+			seed:      "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+			path:      "m",
+			chainCode: "01d28a3e53cffa419ec122c968b3259e16b65076495494d97cae10bbfec3c36f",
+			key:       "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32",
+		},
+		{
+			seed:      "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+			path:      "m/0'",
+			chainCode: "e5fea12a97b927fc9dc3d2cb0d1ea1cf50aa5a1fdc1f933e8906bb38df3377bd",
+			key:       "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			seed, err := hex.DecodeString(tt.seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := accounts.DerivationPath{}
+			if tt.path != "m" {
+				if path, err = accounts.ParseDerivationPath(tt.path); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			key, chainCode, err := derive(seed, path)
+			if err != nil {
+				t.Fatalf("derive: %v", err)
+			}
+			if got := hex.EncodeToString(chainCode); got != tt.chainCode {
+				t.Fatalf("chain code = %s, want %s", got, tt.chainCode)
+			}
+			if got := hex.EncodeToString(math.PaddedBigBytes(key, 32)); got != tt.key {
+				t.Fatalf("key = %s, want %s", got, tt.key)
+			}
+		})
+	}
+}
+
+// Accounts of the development mnemonic used by anvil and hardhat
+func TestDeriveMnemonic(t *testing.T) {
+	const mnemonic = "test test test test test test test test test test test junk"
+	tests := []struct {
+		index   uint32
+		address common.Address
+	}{
+		{index: 0, address: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")},
+		{index: 1, address: common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")},
+		{index: 2, address: common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")},
+	}
+	for _, tt := range tests {
+		path, err := HDPath(DefaultHDPath, tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := Derive(mnemonic, path)
+		if err != nil {
+			t.Fatalf("Derive(%s): %v", path, err)
+		}
+		if got := crypto.PubkeyToAddress(pk.PublicKey); got != tt.address {
+			t.Fatalf("Derive(%s) = %s, want %s", path, got.Hex(), tt.address.Hex())
+		}
+	}
+}
+
+func TestDeriveInvalidMnemonic(t *testing.T) {
+	if _, err := Derive("test test test test test test test test test test test test", DefaultHDPath); err == nil {
+		t.Fatal("Derive accepted a mnemonic with a wrong checksum")
+	}
+}
+
+func TestHDPath(t *testing.T) {
+	path, err := HDPath(DefaultHDPath, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := path.String(); got != "m/44'/60'/0'/0/7" {
+		t.Fatalf("HDPath = %s, want m/44'/60'/0'/0/7", got)
+	}
+	if len(DefaultHDPath) != 4 {
+		t.Fatal("HDPath modified the base path")
+	}
+	if _, err := HDPath(DefaultHDPath, 0x80000000); err == nil {
+		t.Fatal("HDPath accepted a hardened index")
+	}
+}
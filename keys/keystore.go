@@ -0,0 +1,124 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"log"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// Config selects where the private key is loaded from
+type Config struct {
+	// KeystoreFile is a V3 JSON keystore, it takes precedence over HexKey
+	KeystoreFile string
+	// PassphraseFile holds the keystore passphrase
+	PassphraseFile string
+	// HexKey is a plaintext private key, only meant for local development
+	HexKey string
+	// MnemonicFile holds a BIP-39 mnemonic the key is derived from, it takes precedence over HexKey
+	MnemonicFile string
+	// Mnemonic is a plaintext BIP-39 mnemonic, only meant for local development
+	Mnemonic string
+	// HDPath is the derivation path the wallet index is appended to
+	HDPath accounts.DerivationPath
+	// Index selects the wallet derived from the mnemonic
+	Index uint32
+}
+
+// Load returns the private key selected by cfg
+func Load(cfg Config, logger *log.Logger) (*ecdsa.PrivateKey, error) {
+	if cfg.KeystoreFile != "" {
+		passphrase, err := Passphrase(cfg.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return Decrypt(cfg.KeystoreFile, passphrase)
+	}
+
+	if cfg.MnemonicFile != "" || cfg.Mnemonic != "" {
+		return loadHD(cfg, logger)
+	}
+
+	if cfg.HexKey == "" {
+		return nil, errors.New("No key configured, set KEYSTORE_FILE or MNEMONIC_FILE")
+	}
+	logger.Printf("Using plaintext WALLET_KEY, use KEYSTORE_FILE outside of development\n")
+
+	pk, err := crypto.HexToECDSA(cfg.HexKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while parsing private key")
+	}
+	return pk, nil
+}
+
+func loadHD(cfg Config, logger *log.Logger) (*ecdsa.PrivateKey, error) {
+	mnemonic := cfg.Mnemonic
+	if cfg.MnemonicFile != "" {
+		var err error
+		if mnemonic, err = ReadMnemonic(cfg.MnemonicFile); err != nil {
+			return nil, err
+		}
+	} else {
+		logger.Printf("Using plaintext MNEMONIC, use MNEMONIC_FILE outside of development\n")
+	}
+
+	base := cfg.HDPath
+	if base == nil {
+		base = DefaultHDPath
+	}
+	path, err := HDPath(base, cfg.Index)
+	if err != nil {
+		return nil, err
+	}
+	return Derive(mnemonic, path)
+}
+
+// Decrypt reads the V3 keystore at path and decrypts its key
+func Decrypt(path, passphrase string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while reading keystore")
+	}
+
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while decrypting keystore")
+	}
+	return key.PrivateKey, nil
+}
+
+// Import encrypts pk into a new keystore file in dir
+func Import(dir string, pk *ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
+	account, err := newKeyStore(dir).ImportECDSA(pk, passphrase)
+	if err != nil {
+		return accounts.Account{}, errors.Wrap(err, "Error while importing key")
+	}
+	return account, nil
+}
+
+// Create generates a new key and stores it encrypted in dir
+func Create(dir, passphrase string) (accounts.Account, error) {
+	account, err := newKeyStore(dir).NewAccount(passphrase)
+	if err != nil {
+		return accounts.Account{}, errors.Wrap(err, "Error while creating key")
+	}
+	return account, nil
+}
+
+// Find returns the path of the keystore file in dir holding the key of address
+func Find(dir string, address common.Address) (string, error) {
+	account, err := newKeyStore(dir).Find(accounts.Account{Address: address})
+	if err != nil {
+		return "", errors.Wrapf(err, "Error while looking for key %s in %s", address.Hex(), dir)
+	}
+	return account.URL.Path, nil
+}
+
+func newKeyStore(dir string) *keystore.KeyStore {
+	return keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+}
@@ -0,0 +1,96 @@
+package keys
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var discard = log.New(io.Discard, "", 0)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestKeystore(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	account, err := Import(dir, pk, "secret")
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if account.Address != crypto.PubkeyToAddress(pk.PublicKey) {
+		t.Fatalf("Import = %s, want %s", account.Address.Hex(), crypto.PubkeyToAddress(pk.PublicKey).Hex())
+	}
+
+	path, err := Find(dir, account.Address)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	// A trailing newline of the passphrase file is not part of the passphrase
+	loaded, err := Load(Config{KeystoreFile: path, PassphraseFile: writeFile(t, "passphrase", "secret\n"), HexKey: "01"}, discard)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !loaded.Equal(pk) {
+		t.Fatal("Load returned another key")
+	}
+
+	if _, err := Decrypt(path, "wrong"); err == nil {
+		t.Fatal("Decrypt accepted a wrong passphrase")
+	}
+	if _, err := Find(t.TempDir(), account.Address); err == nil {
+		t.Fatal("Find found a key in an empty directory")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	const hexKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	const mnemonic = "test test test test test test test test test test test junk"
+
+	pk, err := Load(Config{HexKey: hexKey}, discard)
+	if err != nil {
+		t.Fatalf("Load of a hex key: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+		t.Fatalf("Load of a hex key = %s", got)
+	}
+
+	// The mnemonic takes precedence over the hex key
+	pk, err = Load(Config{HexKey: hexKey, MnemonicFile: writeFile(t, "mnemonic", mnemonic+"\n"), Index: 1}, discard)
+	if err != nil {
+		t.Fatalf("Load of a mnemonic: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != "0x70997970C51812dc3A010C7d01b50e0d17dc79C8" {
+		t.Fatalf("Load of a mnemonic = %s", got)
+	}
+
+	if _, err := Load(Config{}, discard); err == nil {
+		t.Fatal("Load without a key succeeded")
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	t.Setenv(PassphraseEnv, "from env")
+	if got, err := Passphrase(""); err != nil || got != "from env" {
+		t.Fatalf("Passphrase = %q, %v, want the environment variable", got, err)
+	}
+	if got, err := Passphrase(writeFile(t, "passphrase", "from file\r\n")); err != nil || got != "from file" {
+		t.Fatalf("Passphrase = %q, %v, want the file", got, err)
+	}
+	if _, err := Passphrase(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("Passphrase of a missing file succeeded")
+	}
+}
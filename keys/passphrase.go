@@ -0,0 +1,69 @@
+package keys
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv is the environment variable a keystore passphrase can be read from.
+// It is read from the process environment, never from the .env file.
+const PassphraseEnv = "KEYSTORE_PASSPHRASE"
+
+// Passphrase returns the keystore passphrase from file when it is set, then
+// from PassphraseEnv, and prompts for it on the terminal otherwise
+func Passphrase(file string) (string, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", errors.Wrap(err, "Error while reading passphrase file")
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	if passphrase, ok := os.LookupEnv(PassphraseEnv); ok {
+		return passphrase, nil
+	}
+
+	return Prompt("Keystore passphrase: ")
+}
+
+// NewPassphrase is like Passphrase but asks twice when prompting, so a typo
+// does not lock the key away
+func NewPassphrase(file string) (string, error) {
+	if _, ok := os.LookupEnv(PassphraseEnv); file != "" || ok {
+		return Passphrase(file)
+	}
+
+	passphrase, err := Prompt("New keystore passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	repeated, err := Prompt("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != repeated {
+		return "", errors.New("Passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// Prompt reads a secret from the terminal without echoing it
+func Prompt(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.Errorf("Cannot prompt for secret, stdin is not a terminal, set %s or a passphrase file", PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", errors.Wrap(err, "Error while reading secret")
+	}
+	return string(secret), nil
+}
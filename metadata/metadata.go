@@ -0,0 +1,205 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// Metadata is the operator metadata EigenLayer expects at the metadata URI
+type Metadata struct {
+	Name        string `json:"name"`
+	Website     string `json:"website"`
+	Description string `json:"description"`
+	Logo        string `json:"logo"`
+	Twitter     string `json:"twitter"`
+}
+
+// Limits and patterns of the EigenLayer metadata rules
+const (
+	maxTextLength = 500
+	maxURLLength  = 1024
+	// maxFetchSize is the largest document or logo EigenLayer fetches
+	maxFetchSize = 1 << 20
+	fetchTimeout = 3 * time.Second
+)
+
+var (
+	textPattern    = regexp.MustCompile(`^[a-zA-Z0-9 +.,;:?!'’"\-_/()\[\]~&#$—%]+$`)
+	urlPattern     = regexp.MustCompile(`^(https?)://[^\s/$.?#].[^\s]*$`)
+	twitterPattern = regexp.MustCompile(`^(?:https?://)?(?:www\.)?(?:twitter\.com/\w+|x\.com/\w+)(?:/?|$)`)
+)
+
+// Options relaxes validation for local testing
+type Options struct {
+	// AllowLocal accepts URLs pointing at localhost, which EigenLayer rejects
+	AllowLocal bool
+	// Logo is checked instead of fetching the logo URL when it is set
+	Logo []byte
+}
+
+// Validate checks m against the EigenLayer metadata rules. The logo is
+// fetched to check it is a PNG unless opts.Logo is set.
+func Validate(ctx context.Context, m Metadata, opts Options) error {
+	if err := validateText(m.Name); err != nil {
+		return errors.Wrap(err, "Invalid name")
+	}
+	if err := validateText(m.Description); err != nil {
+		return errors.Wrap(err, "Invalid description")
+	}
+
+	if m.Website != "" {
+		if err := validateURL(m.Website, opts); err != nil {
+			return errors.Wrap(err, "Invalid website")
+		}
+	}
+	if m.Twitter != "" {
+		if err := validateURL(m.Twitter, opts); err != nil {
+			return errors.Wrap(err, "Invalid twitter")
+		}
+		if !twitterPattern.MatchString(m.Twitter) {
+			return errors.New("Invalid twitter, must be a twitter.com or x.com profile")
+		}
+	}
+
+	if m.Logo == "" {
+		return errors.New("Logo is required")
+	}
+	if err := validateURL(m.Logo, opts); err != nil {
+		return errors.Wrap(err, "Invalid logo")
+	}
+	parsed, err := url.Parse(m.Logo)
+	if err != nil {
+		return errors.Wrap(err, "Invalid logo")
+	}
+	if !strings.EqualFold(path.Ext(parsed.Path), ".png") {
+		return errors.New("Invalid logo, only .png logos are accepted")
+	}
+	logo := opts.Logo
+	if logo == nil {
+		if logo, err = get(ctx, m.Logo); err != nil {
+			return errors.Wrap(err, "Error while fetching logo")
+		}
+	}
+	if contentType := http.DetectContentType(logo); contentType != "image/png" {
+		return errors.Errorf("Invalid logo, content is %s instead of image/png", contentType)
+	}
+	return nil
+}
+
+// Encode returns the metadata JSON document
+func Encode(m Metadata) ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while encoding metadata")
+	}
+	return append(data, '\n'), nil
+}
+
+// Decode parses a metadata JSON document
+func Decode(data []byte) (Metadata, error) {
+	var m Metadata
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&m); err != nil {
+		return Metadata{}, errors.Wrap(err, "Error while decoding metadata")
+	}
+	return m, nil
+}
+
+// Hash returns the keccak256 hash of a metadata document
+func Hash(data []byte) common.Hash {
+	return crypto.Keccak256Hash(data)
+}
+
+// Check fetches the metadata document at uri and validates it. When hash is
+// not zero the document must hash to it. It returns the hash of the document.
+func Check(ctx context.Context, uri string, hash common.Hash, opts Options) (common.Hash, error) {
+	if err := validateURL(uri, opts); err != nil {
+		return common.Hash{}, errors.Wrap(err, "Invalid metadata URI")
+	}
+	data, err := get(ctx, uri)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "Error while fetching metadata")
+	}
+
+	actual := Hash(data)
+	if hash != (common.Hash{}) && actual != hash {
+		return actual, errors.Errorf("Metadata at %s has hash %s instead of %s", uri, actual.Hex(), hash.Hex())
+	}
+	m, err := Decode(data)
+	if err != nil {
+		return actual, err
+	}
+	return actual, Validate(ctx, m, Options{AllowLocal: opts.AllowLocal})
+}
+
+func validateText(text string) error {
+	if text == "" {
+		return errors.New("must not be empty")
+	}
+	if len(text) > maxTextLength {
+		return errors.Errorf("must not be longer than %d characters", maxTextLength)
+	}
+	if !textPattern.MatchString(text) {
+		return errors.New("contains characters that are not allowed")
+	}
+	return nil
+}
+
+func validateURL(raw string, opts Options) error {
+	if len(raw) > maxURLLength {
+		return errors.Errorf("URL must not be longer than %d characters", maxURLLength)
+	}
+	if !opts.AllowLocal && (strings.Contains(raw, "localhost") || strings.Contains(raw, "127.0.0.1")) {
+		return errors.Errorf("URL %s points at a local server", raw)
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" || !urlPattern.MatchString(raw) {
+		return errors.Errorf("%q is not a valid http or https URL", raw)
+	}
+	return nil
+}
+
+// get fetches uri the way EigenLayer does, without following redirects and
+// reading at most maxFetchSize bytes
+func get(ctx context.Context, uri string) ([]byte, error) {
+	client := http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: fetchTimeout,
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s returned %s", uri, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFetchSize {
+		return nil, errors.Errorf("%s is larger than %d bytes", uri, maxFetchSize)
+	}
+	return data, nil
+}
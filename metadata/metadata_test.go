@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func valid() Metadata {
+	return Metadata{
+		Name:        "Hello World operator",
+		Website:     "https://example.com",
+		Description: "Answers HelloWorld tasks (since 2024).",
+		Logo:        "https://example.com/logo.png",
+		Twitter:     "https://x.com/example",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Metadata)
+		opts    Options
+		logo    []byte
+		wantErr bool
+	}{
+		{name: "valid"},
+		{name: "twitter.com profile", modify: func(m *Metadata) { m.Twitter = "https://twitter.com/example" }},
+		{name: "no website or twitter", modify: func(m *Metadata) { m.Website, m.Twitter = "", "" }},
+		{name: "empty name", modify: func(m *Metadata) { m.Name = "" }, wantErr: true},
+		{name: "name with markup", modify: func(m *Metadata) { m.Name = "<b>operator</b>" }, wantErr: true},
+		{name: "long description", modify: func(m *Metadata) { m.Description = strings.Repeat("a", maxTextLength+1) }, wantErr: true},
+		{name: "website without scheme", modify: func(m *Metadata) { m.Website = "example.com" }, wantErr: true},
+		{name: "long website", modify: func(m *Metadata) { m.Website = "https://example.com/" + strings.Repeat("a", maxURLLength) }, wantErr: true},
+		{name: "twitter of another site", modify: func(m *Metadata) { m.Twitter = "https://example.com/example" }, wantErr: true},
+		{name: "missing logo", modify: func(m *Metadata) { m.Logo = "" }, wantErr: true},
+		{name: "jpeg logo url", modify: func(m *Metadata) { m.Logo = "https://example.com/logo.jpg" }, wantErr: true},
+		{name: "logo that is not a png", logo: []byte("<html></html>"), wantErr: true},
+		{name: "local logo", modify: func(m *Metadata) { m.Logo = "http://localhost:8080/logo.png" }, wantErr: true},
+		{name: "local logo allowed", modify: func(m *Metadata) { m.Logo = "http://localhost:8080/logo.png" }, opts: Options{AllowLocal: true}},
+		{name: "loopback website", modify: func(m *Metadata) { m.Website = "http://127.0.0.1" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			if tt.modify != nil {
+				tt.modify(&m)
+			}
+			opts := tt.opts
+			if opts.Logo = tt.logo; opts.Logo == nil {
+				opts.Logo = png
+			}
+			if err := Validate(context.Background(), m, opts); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	data, err := Encode(valid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if m != valid() {
+		t.Fatalf("Decode = %+v, want %+v", m, valid())
+	}
+	if _, err := Decode([]byte(`{"name":"operator","color":"red"}`)); err == nil {
+		t.Fatal("Decode accepted an unknown field")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var document []byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metadata.json", func(w http.ResponseWriter, r *http.Request) { w.Write(document) })
+	mux.HandleFunc("/logo.png", func(w http.ResponseWriter, r *http.Request) { w.Write(png) })
+	mux.HandleFunc("/moved.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/metadata.json", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	m := valid()
+	m.Logo = server.URL + "/logo.png"
+	document, err := Encode(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{AllowLocal: true}
+
+	hash, err := Check(context.Background(), server.URL+"/metadata.json", common.Hash{}, opts)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hash != Hash(document) {
+		t.Fatalf("Check = %s, want %s", hash.Hex(), Hash(document).Hex())
+	}
+	if _, err := Check(context.Background(), server.URL+"/metadata.json", hash, opts); err != nil {
+		t.Fatalf("Check with the document hash: %v", err)
+	}
+	if _, err := Check(context.Background(), server.URL+"/metadata.json", common.HexToHash("0x01"), opts); err == nil {
+		t.Fatal("Check accepted a document with another hash")
+	}
+	if _, err := Check(context.Background(), server.URL+"/moved.json", common.Hash{}, opts); err == nil {
+		t.Fatal("Check followed a redirect")
+	}
+	if _, err := Check(context.Background(), server.URL+"/metadata.json", common.Hash{}, Options{}); err == nil {
+		t.Fatal("Check accepted a local metadata URI")
+	}
+}
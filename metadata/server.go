@@ -0,0 +1,35 @@
+package metadata
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Serve serves the files in dir at addr until ctx is cancelled, so metadata
+// and logo can be published on a local network
+func Serve(ctx context.Context, addr, dir string, logger *log.Logger) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           http.FileServer(http.Dir(dir)),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Printf("Error while stopping metadata server: %v\n", err)
+		}
+	}()
+
+	logger.Printf("Serving %s at %s\n", dir, addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "Error while serving metadata")
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package nonce
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// Backend is the part of the Ethereum client the Manager needs
+type Backend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// Manager allocates nonces locally per sender so several transactions from
+// the same key can be in flight at once
+type Manager struct {
+	backend Backend
+	logger  *log.Logger
+
+	mu       sync.Mutex
+	accounts map[common.Address]*account
+}
+
+type account struct {
+	next        uint64
+	synced      bool
+	allocations int
+	// reserved holds nonces that were allocated and not released, either
+	// still being sent or already accepted by the node
+	reserved map[uint64]struct{}
+}
+
+// resyncInterval is the number of allocations after which the local nonce is
+// checked against the node again. This also catches transactions dropped from
+// the mempool and releases reserved nonces that were mined in the meantime.
+const resyncInterval = 100
+
+// New returns a new nonce Manager
+func New(backend Backend, logger *log.Logger) *Manager {
+	return &Manager{
+		backend:  backend,
+		logger:   logger,
+		accounts: make(map[common.Address]*account),
+	}
+}
+
+// Next allocates the next nonce for sender. If the transaction never reaches
+// the node the nonce must be given back with Failed.
+func (m *Manager) Next(ctx context.Context, sender common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(sender)
+	if !acc.synced || acc.allocations >= resyncInterval {
+		if err := m.sync(ctx, sender, acc); err != nil {
+			return 0, err
+		}
+	}
+
+	// After a resync the node may not know about nonces that are still being sent
+	for {
+		if _, ok := acc.reserved[acc.next]; !ok {
+			break
+		}
+		acc.next++
+	}
+
+	nonce := acc.next
+	acc.reserved[nonce] = struct{}{}
+	acc.next++
+	acc.allocations++
+	return nonce, nil
+}
+
+// Failed releases a nonce whose transaction never reached the node. Releasing
+// anything but the latest nonce leaves a gap that would hold up every later
+// transaction, so the next allocation resyncs with the node to fill it.
+func (m *Manager) Failed(sender common.Address, nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc := m.account(sender)
+	delete(acc.reserved, nonce)
+
+	if nonce+1 == acc.next {
+		acc.next = nonce
+		return
+	}
+
+	m.logger.Printf("Nonce gap detected for %s at nonce %d, resyncing\n", sender.Hex(), nonce)
+	acc.synced = false
+}
+
+// Resync makes the next allocation for sender start from the node's pending nonce
+func (m *Manager) Resync(sender common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.account(sender).synced = false
+}
+
+// Send allocates a nonce for sender and calls send with it. The nonce is
+// released when send fails, and nonce errors from the node trigger a resync.
+func (m *Manager) Send(ctx context.Context, sender common.Address, send func(nonce uint64) (*types.Transaction, error)) (*types.Transaction, error) {
+	nonce, err := m.Next(ctx, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := send(nonce)
+	if err != nil {
+		m.Failed(sender, nonce)
+		if IsNonceError(err) {
+			m.Resync(sender)
+		}
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// Gaps returns nonces between the node's pending nonce and the highest
+// reserved one that were never sent
+func (m *Manager) Gaps(ctx context.Context, sender common.Address) ([]uint64, error) {
+	pending, err := m.backend.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting nonce")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.account(sender).gaps(pending), nil
+}
+
+func (m *Manager) account(sender common.Address) *account {
+	acc, ok := m.accounts[sender]
+	if !ok {
+		acc = &account{reserved: make(map[uint64]struct{})}
+		m.accounts[sender] = acc
+	}
+	return acc
+}
+
+func (m *Manager) sync(ctx context.Context, sender common.Address, acc *account) error {
+	pending, err := m.backend.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return errors.Wrap(err, "Error while getting nonce")
+	}
+
+	if gaps := acc.gaps(pending); len(gaps) > 0 {
+		m.logger.Printf("Filling nonce gaps %v for %s\n", gaps, sender.Hex())
+	}
+
+	for n := range acc.reserved {
+		if n < pending {
+			delete(acc.reserved, n)
+		}
+	}
+	acc.next = pending
+	acc.synced = true
+	acc.allocations = 0
+	return nil
+}
+
+func (acc *account) gaps(pending uint64) []uint64 {
+	var highest uint64
+	found := false
+	for n := range acc.reserved {
+		if n >= pending && (!found || n > highest) {
+			highest, found = n, true
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	var gaps []uint64
+	for n := pending; n < highest; n++ {
+		if _, ok := acc.reserved[n]; !ok {
+			gaps = append(gaps, n)
+		}
+	}
+	return gaps
+}
+
+// IsNonceError reports whether err was returned by the node because of a wrong nonce
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "already known") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
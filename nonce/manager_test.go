@@ -0,0 +1,144 @@
+package nonce
+
+import (
+	"context"
+	"io"
+	"log"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+type testBackend struct {
+	pending uint64
+	calls   int
+}
+
+func (b *testBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.calls++
+	return b.pending, nil
+}
+
+var sender = common.HexToAddress("0x01")
+
+func newTestManager(pending uint64) (*Manager, *testBackend) {
+	backend := &testBackend{pending: pending}
+	return New(backend, log.New(io.Discard, "", 0)), backend
+}
+
+func next(t *testing.T, m *Manager) uint64 {
+	t.Helper()
+	nonce, err := m.Next(context.Background(), sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return nonce
+}
+
+func TestNext(t *testing.T) {
+	m, backend := newTestManager(5)
+	for want := uint64(5); want < 8; want++ {
+		if got := next(t, m); got != want {
+			t.Fatalf("Next = %d, want %d", got, want)
+		}
+	}
+	if backend.calls != 1 {
+		t.Fatalf("synced %d times, want once", backend.calls)
+	}
+}
+
+func TestFailedLatest(t *testing.T) {
+	m, backend := newTestManager(5)
+	next(t, m)
+	nonce := next(t, m)
+	m.Failed(sender, nonce)
+	if got := next(t, m); got != nonce {
+		t.Fatalf("Next after releasing the latest nonce = %d, want %d", got, nonce)
+	}
+	if backend.calls != 1 {
+		t.Fatalf("synced %d times, want once", backend.calls)
+	}
+}
+
+// Releasing an earlier nonce leaves a gap, the next allocation resyncs and
+// fills it without reusing nonces still being sent
+func TestFailedGap(t *testing.T) {
+	m, backend := newTestManager(5)
+	first, second, third := next(t, m), next(t, m), next(t, m)
+	m.Failed(sender, first)
+
+	gaps, err := m.Gaps(context.Background(), sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(gaps, []uint64{first}) {
+		t.Fatalf("Gaps = %v, want [%d]", gaps, first)
+	}
+
+	if got := next(t, m); got != first {
+		t.Fatalf("Next after a gap = %d, want %d", got, first)
+	}
+	if got := next(t, m); got != third+1 {
+		t.Fatalf("Next = %d, want %d past the reserved %d and %d", got, third+1, second, third)
+	}
+	if backend.calls != 3 {
+		t.Fatalf("synced %d times, want 3", backend.calls)
+	}
+}
+
+func TestResyncReleasesMined(t *testing.T) {
+	m, backend := newTestManager(5)
+	next(t, m)
+	next(t, m)
+	backend.pending = 7
+	m.Resync(sender)
+	if got := next(t, m); got != 7 {
+		t.Fatalf("Next after resync = %d, want 7", got)
+	}
+	if len(m.account(sender).reserved) != 1 {
+		t.Fatalf("reserved = %v, want only nonce 7", m.account(sender).reserved)
+	}
+}
+
+func TestSend(t *testing.T) {
+	m, backend := newTestManager(5)
+	tx, err := m.Send(context.Background(), sender, func(nonce uint64) (*types.Transaction, error) {
+		return types.NewTx(&types.LegacyTx{Nonce: nonce}), nil
+	})
+	if err != nil || tx.Nonce() != 5 {
+		t.Fatalf("Send = %v, %v, want nonce 5", tx, err)
+	}
+
+	// The node knows a higher nonce, the failed one is released and resynced
+	backend.pending = 9
+	if _, err := m.Send(context.Background(), sender, func(nonce uint64) (*types.Transaction, error) {
+		return nil, errors.New("nonce too low: next nonce 9, tx nonce 6")
+	}); err == nil {
+		t.Fatal("Send succeeded although sending failed")
+	}
+	if got := next(t, m); got != 9 {
+		t.Fatalf("Next after a nonce error = %d, want 9", got)
+	}
+}
+
+func TestIsNonceError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: nil},
+		{err: errors.New("insufficient funds for gas * price + value")},
+		{err: errors.New("Nonce too low"), want: true},
+		{err: errors.New("nonce too high"), want: true},
+		{err: errors.Wrap(errors.New("already known"), "Error while sending"), want: true},
+		{err: errors.New("replacement transaction underpriced"), want: true},
+	}
+	for _, tt := range tests {
+		if got := IsNonceError(tt.err); got != tt.want {
+			t.Fatalf("IsNonceError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package onboarding
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/internal/fsutil"
+)
+
+// Step is an onboarding step of an operator
+type Step string
+
+// Onboarding steps, in the order they are taken
+const (
+	EigenLayer    Step = "eigenlayer"
+	Deposit       Step = "deposit"
+	AVS           Step = "avs"
+	StakeRegistry Step = "stake-registry"
+	MinimumWeight Step = "minimum-weight"
+	// Done means every required step is complete
+	Done Step = "done"
+)
+
+// Steps lists the onboarding steps in order
+var Steps = []Step{EigenLayer, Deposit, AVS, StakeRegistry, MinimumWeight}
+
+var descriptions = map[Step]string{
+	EigenLayer:    "EigenLayer operator registration",
+	Deposit:       "strategy deposit",
+	AVS:           "AVS registration",
+	StakeRegistry: "stake registry registration",
+	MinimumWeight: "minimum weight",
+	Done:          "onboarding complete",
+}
+
+// Description returns a readable name of the step
+func (s Step) Description() string {
+	if description, ok := descriptions[s]; ok {
+		return description
+	}
+	return string(s)
+}
+
+// Next returns the step taken after s, Done after the last one
+func (s Step) Next() Step {
+	for i, step := range Steps[:len(Steps)-1] {
+		if step == s {
+			return Steps[i+1]
+		}
+	}
+	return Done
+}
+
+// Skippable reports whether the step may be made optional. Registration steps
+// are always required, steps that depend on stakers are not.
+func (s Step) Skippable() bool {
+	return s == Deposit || s == MinimumWeight
+}
+
+// ParseSteps parses a comma separated list of steps
+func ParseSteps(list string) ([]Step, error) {
+	var steps []Step
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		step := Step(name)
+		if _, ok := descriptions[step]; !ok || step == Done {
+			return nil, errors.Errorf("Unknown onboarding step %q", name)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// Progress is how far onboarding of an operator got
+type Progress struct {
+	Operator common.Address `json:"operator"`
+	// Step is the first required step that is not complete, Done when there is none
+	Step      Step   `json:"step"`
+	Completed []Step `json:"completed"`
+	// Skipped are optional steps that are not complete
+	Skipped []Step `json:"skipped,omitempty"`
+	// Waiting is what the current step waits for when the operator cannot take it itself
+	Waiting string `json:"waiting,omitempty"`
+	// LastError is why the current step failed last
+	LastError string    `json:"lastError,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Number returns the position of the current step counting from 1, or
+// len(Steps)+1 when onboarding is done
+func (p Progress) Number() int {
+	for i, step := range Steps {
+		if step == p.Step {
+			return i + 1
+		}
+	}
+	return len(Steps) + 1
+}
+
+// Store persists onboarding progress on disk
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// New returns a new Store backed by file at given path
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the saved progress, ok is false if nothing was saved yet
+func (s *Store) Load() (progress Progress, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Progress{}, false, nil
+	}
+	if err != nil {
+		return Progress{}, false, errors.Wrap(err, "Error while reading onboarding file")
+	}
+
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return Progress{}, false, errors.Wrap(err, "Error while decoding onboarding file")
+	}
+	return progress, true, nil
+}
+
+// Save atomically replaces the saved progress
+func (s *Store) Save(progress Progress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Error while encoding onboarding progress")
+	}
+
+	if err := fsutil.WriteFileAtomic(s.path, data); err != nil {
+		return errors.Wrap(err, "Error while saving onboarding file")
+	}
+	return nil
+}
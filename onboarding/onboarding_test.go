@@ -0,0 +1,84 @@
+package onboarding
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNext(t *testing.T) {
+	for i, step := range Steps {
+		want := Done
+		if i+1 < len(Steps) {
+			want = Steps[i+1]
+		}
+		if got := step.Next(); got != want {
+			t.Fatalf("%s.Next() = %s, want %s", step, got, want)
+		}
+	}
+	if got := Done.Next(); got != Done {
+		t.Fatalf("Done.Next() = %s, want Done", got)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	if got := (Progress{Step: EigenLayer}).Number(); got != 1 {
+		t.Fatalf("Number = %d, want 1", got)
+	}
+	if got := (Progress{Step: Done}).Number(); got != len(Steps)+1 {
+		t.Fatalf("Number = %d, want %d", got, len(Steps)+1)
+	}
+}
+
+func TestParseSteps(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []Step
+		wantErr bool
+	}{
+		{list: ""},
+		{list: "deposit, minimum-weight", want: []Step{Deposit, MinimumWeight}},
+		{list: "deposit,,", want: []Step{Deposit}},
+		{list: "done", wantErr: true},
+		{list: "stake", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSteps(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseSteps(%q) error = %v, want error %v", tt.list, err, tt.wantErr)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("ParseSteps(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := New(filepath.Join(t.TempDir(), "onboarding.json"))
+	if _, ok, err := store.Load(); err != nil || ok {
+		t.Fatalf("Load of a missing file = %v, %v, want nothing saved", ok, err)
+	}
+
+	progress := Progress{
+		Operator:  common.HexToAddress("0x01"),
+		Step:      AVS,
+		Completed: []Step{EigenLayer},
+		Skipped:   []Step{Deposit},
+		LastError: "reverted",
+		UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if err := store.Save(progress); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, ok, err := store.Load()
+	if err != nil || !ok {
+		t.Fatalf("Load = %v, %v", ok, err)
+	}
+	if got.Operator != progress.Operator || got.Step != progress.Step || got.LastError != progress.LastError ||
+		!got.UpdatedAt.Equal(progress.UpdatedAt) || !slices.Equal(got.Completed, progress.Completed) || !slices.Equal(got.Skipped, progress.Skipped) {
+		t.Fatalf("Load = %+v, want %+v", got, progress)
+	}
+}
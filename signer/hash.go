@@ -0,0 +1,30 @@
+package signer
+
+import (
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+)
+
+// Keccak256 returns the keccak256 digest of data
+func Keccak256(data ...[]byte) common.Hash {
+	return crypto.Keccak256Hash(data...)
+}
+
+// TextHash returns the EIP-191 digest of msg, keccak256 of
+// "\x19Ethereum Signed Message:\n" + len(msg) + msg. For a 32 byte hash this is
+// what OpenZeppelin's toEthSignedMessageHash computes on-chain.
+func TextHash(msg []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(msg))
+}
+
+// TypedDataHash returns the EIP-712 digest of data
+func TypedDataHash(data apitypes.TypedData) (common.Hash, error) {
+	digest, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "Error while hashing typed data")
+	}
+	return common.BytesToHash(digest), nil
+}
@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// KeyRing holds the signing keys of an operator by the block they take effect
+// at, so a task is always signed with the key the stake registry expects for
+// the task's block. Keys are switched atomically for every later block.
+type KeyRing struct {
+	mu      sync.RWMutex
+	entries []ringEntry
+}
+
+type ringEntry struct {
+	from    uint64
+	address common.Address
+	// signer is nil when the key is known on-chain but could not be loaded
+	signer Signer
+}
+
+// NewKeyRing returns a KeyRing using initial for every block
+func NewKeyRing(initial Signer) *KeyRing {
+	return &KeyRing{entries: []ringEntry{{address: initial.Address(), signer: initial}}}
+}
+
+// Add makes address the signing key from block from on. When s is nil the key
+// is recorded but signing for those blocks fails rather than falling back to
+// an older key.
+func (r *KeyRing) Add(from uint64, address common.Address, s Signer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := ringEntry{from: from, address: address, signer: s}
+	i := sort.Search(len(r.entries), func(i int) bool { return r.entries[i].from >= from })
+	if i < len(r.entries) && r.entries[i].from == from {
+		r.entries[i] = entry
+		return
+	}
+	r.entries = append(r.entries, ringEntry{})
+	copy(r.entries[i+1:], r.entries[i:])
+	r.entries[i] = entry
+}
+
+// At returns the signing key in effect at block
+func (r *KeyRing) At(block uint64) (Signer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry := r.entries[0]
+	for _, e := range r.entries[1:] {
+		if e.from > block {
+			break
+		}
+		entry = e
+	}
+	if entry.signer == nil {
+		return nil, errors.Errorf("Signing key %s in effect at block %d is not loaded", entry.address.Hex(), block)
+	}
+	return entry.signer, nil
+}
+
+// Latest returns the address of the most recent signing key
+func (r *KeyRing) Latest() common.Address {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.entries[len(r.entries)-1].address
+}
@@ -0,0 +1,82 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestLocal(t *testing.T) *Local {
+	t.Helper()
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewLocal(pk)
+}
+
+func TestKeyRingAt(t *testing.T) {
+	initial, second, third := newTestLocal(t), newTestLocal(t), newTestLocal(t)
+	ring := NewKeyRing(initial)
+	// Added out of order, as rotations found by a backfill may be
+	ring.Add(200, third.Address(), third)
+	ring.Add(100, second.Address(), second)
+
+	tests := []struct {
+		block uint64
+		want  Signer
+	}{
+		{block: 0, want: initial},
+		{block: 99, want: initial},
+		{block: 100, want: second},
+		{block: 199, want: second},
+		{block: 200, want: third},
+		{block: 1 << 40, want: third},
+	}
+	for _, tt := range tests {
+		got, err := ring.At(tt.block)
+		if err != nil {
+			t.Fatalf("At(%d): %v", tt.block, err)
+		}
+		if got.Address() != tt.want.Address() {
+			t.Fatalf("At(%d) = %s, want %s", tt.block, got.Address().Hex(), tt.want.Address().Hex())
+		}
+	}
+	if latest := ring.Latest(); latest != third.Address() {
+		t.Fatalf("Latest = %s, want %s", latest.Hex(), third.Address().Hex())
+	}
+}
+
+func TestKeyRingReplace(t *testing.T) {
+	initial, first, second := newTestLocal(t), newTestLocal(t), newTestLocal(t)
+	ring := NewKeyRing(initial)
+	ring.Add(100, first.Address(), first)
+	ring.Add(100, second.Address(), second)
+
+	got, err := ring.At(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Address() != second.Address() {
+		t.Fatalf("At(100) = %s, want the key added last %s", got.Address().Hex(), second.Address().Hex())
+	}
+}
+
+// A key known on-chain but not loaded never falls back to an older key
+func TestKeyRingNotLoaded(t *testing.T) {
+	initial := newTestLocal(t)
+	ring := NewKeyRing(initial)
+	missing := common.HexToAddress("0x01")
+	ring.Add(100, missing, nil)
+
+	if _, err := ring.At(150); err == nil {
+		t.Fatal("At returned a signer for a key that is not loaded")
+	}
+	if got, err := ring.At(99); err != nil || got.Address() != initial.Address() {
+		t.Fatalf("At(99) = %v, %v, want the initial key", got, err)
+	}
+	if latest := ring.Latest(); latest != missing {
+		t.Fatalf("Latest = %s, want %s", latest.Hex(), missing.Hex())
+	}
+}
@@ -0,0 +1,179 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+)
+
+// Remote signs through an endpoint speaking the Web3Signer eth1 JSON-RPC
+// methods eth_accounts, eth_sign, eth_signTypedData and eth_signTransaction.
+// The key never leaves the signer, and every signature it returns is
+// recovered and checked against the account before it is used.
+type Remote struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// txArgs is the transaction object of eth_signTransaction
+type txArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// NewRemote connects to the signer at url. When address is empty the first
+// account of the signer is used.
+func NewRemote(ctx context.Context, url, address string) (*Remote, error) {
+	if url == "" {
+		return nil, errors.New("Remote signer url is not set")
+	}
+
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while connecting to remote signer")
+	}
+
+	var accounts []common.Address
+	if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "Error while listing remote signer accounts")
+	}
+
+	r := &Remote{client: client}
+	switch {
+	case address != "":
+		r.address = common.HexToAddress(address)
+		if !contains(accounts, r.address) {
+			client.Close()
+			return nil, errors.Errorf("Remote signer has no key for %s", r.address.Hex())
+		}
+	case len(accounts) > 0:
+		r.address = accounts[0]
+	default:
+		client.Close()
+		return nil, errors.New("Remote signer has no accounts")
+	}
+	return r, nil
+}
+
+// Address returns the remote account
+func (r *Remote) Address() common.Address {
+	return r.address
+}
+
+// SignText signs the EIP-191 digest of msg with eth_sign
+func (r *Remote) SignText(ctx context.Context, msg []byte) ([]byte, error) {
+	var sig hexutil.Bytes
+	if err := r.client.CallContext(ctx, &sig, "eth_sign", r.address, hexutil.Bytes(msg)); err != nil {
+		return nil, errors.Wrap(err, "Error while signing message remotely")
+	}
+	return r.checked(TextHash(msg), sig)
+}
+
+// SignTypedData signs the EIP-712 digest of data with eth_signTypedData
+func (r *Remote) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	digest, err := TypedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig hexutil.Bytes
+	if err := r.client.CallContext(ctx, &sig, "eth_signTypedData", r.address, data); err != nil {
+		return nil, errors.Wrap(err, "Error while signing typed data remotely")
+	}
+	return r.checked(digest, sig)
+}
+
+// SignTx signs tx with eth_signTransaction
+func (r *Remote) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := txArgs{
+		From:    r.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+
+	var raw hexutil.Bytes
+	if err := r.client.CallContext(ctx, &raw, "eth_signTransaction", args); err != nil {
+		return nil, errors.Wrap(err, "Error while signing transaction remotely")
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, errors.Wrap(err, "Error while decoding remotely signed transaction")
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while recovering transaction sender")
+	}
+	if sender != r.address || !sameCall(signed, tx, chainID) {
+		return nil, errors.Wrap(ErrInvalidSignature, "Remote signer returned a different transaction")
+	}
+	return signed, nil
+}
+
+// Close closes the connection to the signer
+func (r *Remote) Close() {
+	r.client.Close()
+}
+
+func (r *Remote) checked(digest common.Hash, sig []byte) ([]byte, error) {
+	if err := Verify(digest, sig, r.address); err != nil {
+		return nil, errors.Wrap(err, "Error while verifying remote signature")
+	}
+	// Some signers return 0/1 recovery ids, on-chain verifiers expect 27/28
+	if sig[SignatureLength-1] < 27 {
+		sig[SignatureLength-1] += 27
+	}
+	return sig, nil
+}
+
+// sameCall reports whether the signed transaction does what tx asked for on
+// chainID, at the fees it asked for
+func sameCall(signed, tx *types.Transaction, chainID *big.Int) bool {
+	sameTo := (signed.To() == nil && tx.To() == nil) ||
+		(signed.To() != nil && tx.To() != nil && *signed.To() == *tx.To())
+	return sameTo &&
+		signed.ChainId().Cmp(chainID) == 0 &&
+		signed.Nonce() == tx.Nonce() &&
+		signed.Gas() == tx.Gas() &&
+		signed.GasPrice().Cmp(tx.GasPrice()) == 0 &&
+		signed.GasFeeCap().Cmp(tx.GasFeeCap()) == 0 &&
+		signed.GasTipCap().Cmp(tx.GasTipCap()) == 0 &&
+		signed.Value().Cmp(tx.Value()) == 0 &&
+		bytes.Equal(signed.Data(), tx.Data())
+}
+
+func contains(accounts []common.Address, address common.Address) bool {
+	for _, account := range accounts {
+		if account == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSameCall(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(17000)
+	to := common.HexToAddress("0x01")
+	dynamic := func(modify func(*types.DynamicFeeTx)) *types.DynamicFeeTx {
+		tx := &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     3,
+			GasTipCap: big.NewInt(2),
+			GasFeeCap: big.NewInt(30),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(1),
+			Data:      []byte{0x01},
+		}
+		if modify != nil {
+			modify(tx)
+		}
+		return tx
+	}
+	legacy := func(modify func(*types.LegacyTx)) *types.LegacyTx {
+		tx := &types.LegacyTx{Nonce: 3, GasPrice: big.NewInt(30), Gas: 21000, To: &to, Value: big.NewInt(1), Data: []byte{0x01}}
+		if modify != nil {
+			modify(tx)
+		}
+		return tx
+	}
+
+	tests := []struct {
+		name    string
+		tx      types.TxData
+		signed  types.TxData
+		chainID *big.Int
+		want    bool
+	}{
+		{name: "same dynamic fee tx", tx: dynamic(nil), signed: dynamic(nil), chainID: chainID, want: true},
+		{name: "same legacy tx", tx: legacy(nil), signed: legacy(nil), chainID: chainID, want: true},
+		{name: "other recipient", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.To = &common.Address{} }), chainID: chainID},
+		{name: "contract creation", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.To = nil }), chainID: chainID},
+		{name: "other nonce", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.Nonce++ }), chainID: chainID},
+		{name: "other gas", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.Gas++ }), chainID: chainID},
+		{name: "other value", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(2) }), chainID: chainID},
+		{name: "other data", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.Data = nil }), chainID: chainID},
+		{name: "higher fee cap", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(300) }), chainID: chainID},
+		{name: "higher tip", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(20) }), chainID: chainID},
+		{name: "higher gas price", tx: legacy(nil), signed: legacy(func(tx *types.LegacyTx) { tx.GasPrice = big.NewInt(300) }), chainID: chainID},
+		{name: "other chain", tx: dynamic(nil), signed: dynamic(func(tx *types.DynamicFeeTx) { tx.ChainID = big.NewInt(1) }), chainID: chainID},
+		{name: "legacy for another chain", tx: legacy(nil), signed: legacy(nil), chainID: big.NewInt(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := types.NewTx(tt.tx)
+			signingChain := chainID
+			if dynamicTx, ok := tt.signed.(*types.DynamicFeeTx); ok {
+				signingChain = dynamicTx.ChainID
+			}
+			signed, err := types.SignNewTx(pk, types.LatestSignerForChainID(signingChain), tt.signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sameCall(signed, tx, tt.chainID); got != tt.want {
+				t.Fatalf("sameCall = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package signer_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/signer/signertest"
+)
+
+func newRemote(t *testing.T, address string, pks ...*ecdsa.PrivateKey) *signer.Remote {
+	t.Helper()
+	server, err := signertest.NewFakeServer(pks...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+
+	remote, err := signer.NewRemote(context.Background(), server.URL(), address)
+	if err != nil {
+		t.Fatalf("NewRemote: %v", err)
+	}
+	t.Cleanup(remote.Close)
+	return remote
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
+func TestNewRemote(t *testing.T) {
+	first, second := generateKey(t), generateKey(t)
+
+	if remote := newRemote(t, "", first, second); remote.Address() != crypto.PubkeyToAddress(first.PublicKey) {
+		t.Fatalf("Address = %s, want the first account", remote.Address().Hex())
+	}
+	selected := crypto.PubkeyToAddress(second.PublicKey)
+	if remote := newRemote(t, selected.Hex(), first, second); remote.Address() != selected {
+		t.Fatalf("Address = %s, want %s", remote.Address().Hex(), selected.Hex())
+	}
+
+	server, err := signertest.NewFakeServer(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	if _, err := signer.NewRemote(context.Background(), server.URL(), selected.Hex()); err == nil {
+		t.Fatal("NewRemote with an unknown account succeeded")
+	}
+	if _, err := signer.NewRemote(context.Background(), "", ""); err == nil {
+		t.Fatal("NewRemote without url succeeded")
+	}
+}
+
+func TestRemoteSignText(t *testing.T) {
+	pk := generateKey(t)
+	remote := newRemote(t, "", pk)
+	msg := []byte("Hello, Alice")
+
+	sig, err := remote.SignText(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("SignText: %v", err)
+	}
+	want, err := signer.SignText(pk, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hexutil.Encode(sig) != hexutil.Encode(want) {
+		t.Fatalf("SignText = %x, want %x", sig, want)
+	}
+	if err := signer.Verify(signer.TextHash(msg), sig, remote.Address()); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestRemoteSignTypedData(t *testing.T) {
+	pk := generateKey(t)
+	remote := newRemote(t, "", pk)
+	data := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"TaskResponse": {
+				{Name: "message", Type: "string"},
+			},
+		},
+		PrimaryType: "TaskResponse",
+		Domain:      apitypes.TypedDataDomain{Name: "HelloWorldServiceManager", ChainId: (*math.HexOrDecimal256)(big.NewInt(17000))},
+		Message:     apitypes.TypedDataMessage{"message": "Hello, Alice"},
+	}
+
+	sig, err := remote.SignTypedData(context.Background(), data)
+	if err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	digest, err := signer.TypedDataHash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := signer.Verify(digest, sig, remote.Address()); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestRemoteSignTx(t *testing.T) {
+	pk := generateKey(t)
+	remote := newRemote(t, "", pk)
+	chainID := big.NewInt(17000)
+	to := common.HexToAddress("0x01")
+
+	for name, tx := range map[string]*types.Transaction{
+		"dynamic fee": types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(30), Gas: 21000, To: &to, Value: big.NewInt(1), Data: []byte{0x01}}),
+		"legacy":      types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(30), Gas: 21000, To: &to, Value: big.NewInt(1)}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			signed, err := remote.SignTx(context.Background(), tx, chainID)
+			if err != nil {
+				t.Fatalf("SignTx: %v", err)
+			}
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sender != remote.Address() {
+				t.Fatalf("sender = %s, want %s", sender.Hex(), remote.Address().Hex())
+			}
+			if signed.Nonce() != tx.Nonce() || signed.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 || signed.ChainId().Cmp(chainID) != 0 {
+				t.Fatalf("signed transaction differs from the requested one")
+			}
+		})
+	}
+}
+
+// lyingService lists one account but signs with another key
+type lyingService struct {
+	account common.Address
+	pk      *ecdsa.PrivateKey
+}
+
+func (s *lyingService) Accounts() []common.Address {
+	return []common.Address{s.account}
+}
+
+func (s *lyingService) Sign(address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	return signer.SignText(s.pk, data)
+}
+
+func TestRemoteRejectsOtherSigner(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &lyingService{account: common.HexToAddress("0x01"), pk: generateKey(t)}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	endpoint := httptest.NewServer(server)
+	defer endpoint.Close()
+
+	remote, err := signer.NewRemote(context.Background(), endpoint.URL, "")
+	if err != nil {
+		t.Fatalf("NewRemote: %v", err)
+	}
+	defer remote.Close()
+	if _, err := remote.SignText(context.Background(), []byte("Hello, Alice")); !errors.Is(err, signer.ErrInvalidSignature) {
+		t.Fatalf("SignText = %v, want ErrInvalidSignature", err)
+	}
+}
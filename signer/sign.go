@@ -0,0 +1,38 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+)
+
+// SignatureLength is the length of r || s || v signatures
+const SignatureLength = crypto.SignatureLength
+
+// Sign signs digest and returns a 65 byte r || s || v signature with v set to
+// 27 or 28, the form ecrecover and OpenZeppelin's ECDSA.recover accept
+func Sign(pk *ecdsa.PrivateKey, digest common.Hash) ([]byte, error) {
+	sig, err := crypto.Sign(digest.Bytes(), pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while signing digest")
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// SignText signs the EIP-191 digest of msg
+func SignText(pk *ecdsa.PrivateKey, msg []byte) ([]byte, error) {
+	return Sign(pk, TextHash(msg))
+}
+
+// SignTypedData signs the EIP-712 digest of data
+func SignTypedData(pk *ecdsa.PrivateKey, data apitypes.TypedData) ([]byte, error) {
+	digest, err := TypedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+	return Sign(pk, digest)
+}
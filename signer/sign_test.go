@@ -0,0 +1,138 @@
+package signer
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signatures published by other implementations. Matching them shows our
+// digests and signatures are the ones an on-chain verifier expects.
+var vectors = []struct {
+	name      string
+	key       string
+	address   common.Address
+	digest    func() (common.Hash, error)
+	expected  common.Hash
+	signature string
+}{
+	{
+		// web3.js accounts.sign documentation
+		name:    "eip191 text",
+		key:     "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		address: common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"),
+		digest: func() (common.Hash, error) {
+			return TextHash([]byte("Some data")), nil
+		},
+		expected:  common.HexToHash("0x1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"),
+		signature: "0xb91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c",
+	},
+	{
+		// EIP-712 specification example, signed with keccak256("cow")
+		name:    "eip712 mail",
+		key:     "c85ef7d79691fe79573b1a7064c19c1a9819ebdbd1faaab1a8ec92344438aaf4",
+		address: common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"),
+		digest: func() (common.Hash, error) {
+			return TypedDataHash(mailTypedData())
+		},
+		expected:  common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"),
+		signature: "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c",
+	},
+}
+
+func TestSignVectors(t *testing.T) {
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			digest, err := v.digest()
+			if err != nil {
+				t.Fatalf("digest: %v", err)
+			}
+			if digest != v.expected {
+				t.Fatalf("digest = %s, want %s", digest.Hex(), v.expected.Hex())
+			}
+
+			pk, err := crypto.HexToECDSA(v.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if address := crypto.PubkeyToAddress(pk.PublicKey); address != v.address {
+				t.Fatalf("address = %s, want %s", address.Hex(), v.address.Hex())
+			}
+			sig, err := Sign(pk, digest)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if want := hexutil.MustDecode(v.signature); !bytes.Equal(sig, want) {
+				t.Fatalf("signature = %s, want %s", hexutil.Encode(sig), v.signature)
+			}
+			if err := Verify(digest, sig, v.address); err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestSignTextAndTypedData(t *testing.T) {
+	pk, err := crypto.HexToECDSA(vectors[0].key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := SignText(pk, []byte("Some data"))
+	if err != nil {
+		t.Fatalf("SignText: %v", err)
+	}
+	if hexutil.Encode(sig) != vectors[0].signature {
+		t.Fatalf("SignText = %s, want %s", hexutil.Encode(sig), vectors[0].signature)
+	}
+
+	pk, err = crypto.HexToECDSA(vectors[1].key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig, err = SignTypedData(pk, mailTypedData()); err != nil {
+		t.Fatalf("SignTypedData: %v", err)
+	}
+	if hexutil.Encode(sig) != vectors[1].signature {
+		t.Fatalf("SignTypedData = %s, want %s", hexutil.Encode(sig), vectors[1].signature)
+	}
+}
+
+func mailTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainId:           (*math.HexOrDecimal256)(big.NewInt(1)),
+			VerifyingContract: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC",
+		},
+		Message: apitypes.TypedDataMessage{
+			"from":     map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to":       map[string]interface{}{"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!",
+		},
+	}
+}
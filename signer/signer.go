@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/keys"
+)
+
+const (
+	// LocalType signs with a key loaded into the process
+	LocalType = "local"
+	// RemoteType signs through a Web3Signer compatible endpoint
+	RemoteType = "remote"
+)
+
+// Signer signs messages and transactions for a single address
+type Signer interface {
+	// Address returns the address signatures recover to
+	Address() common.Address
+	// SignText returns a 65 byte signature over the EIP-191 digest of msg
+	SignText(ctx context.Context, msg []byte) ([]byte, error)
+	// SignTypedData returns a 65 byte signature over the EIP-712 digest of data
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+	// SignTx returns tx signed for chainID
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Config selects and configures a Signer
+type Config struct {
+	Type string
+	// Keys is where the local signer loads its key from
+	Keys keys.Config
+	// URL is the remote signer endpoint
+	URL string
+	// Address is the remote signer account, the first account of the endpoint when empty
+	Address string
+}
+
+// New returns the Signer selected by cfg
+func New(ctx context.Context, cfg Config, logger *log.Logger) (Signer, error) {
+	switch cfg.Type {
+	case LocalType, "":
+		pk, err := keys.Load(cfg.Keys, logger)
+		if err != nil {
+			return nil, err
+		}
+		return NewLocal(pk), nil
+	case RemoteType:
+		return NewRemote(ctx, cfg.URL, cfg.Address)
+	default:
+		return nil, errors.Errorf("Unknown signer type %q", cfg.Type)
+	}
+}
+
+// TransactOpts returns transaction options signing with s. Transactions are
+// signed with ctx, as bind does not pass a context to signers.
+func TransactOpts(ctx context.Context, s Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    s.Address(),
+		Context: ctx,
+		Value:   big.NewInt(0),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != s.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(ctx, tx, chainID)
+		},
+	}
+}
+
+// Local signs with a private key held in memory
+type Local struct {
+	pk      *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewLocal returns a Signer for pk
+func NewLocal(pk *ecdsa.PrivateKey) *Local {
+	return &Local{pk: pk, address: crypto.PubkeyToAddress(pk.PublicKey)}
+}
+
+// Address returns the address of the key
+func (l *Local) Address() common.Address {
+	return l.address
+}
+
+// SignText signs the EIP-191 digest of msg
+func (l *Local) SignText(ctx context.Context, msg []byte) ([]byte, error) {
+	return SignText(l.pk, msg)
+}
+
+// SignTypedData signs the EIP-712 digest of data
+func (l *Local) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	return SignTypedData(l.pk, data)
+}
+
+// SignTx signs tx for chainID
+func (l *Local) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), l.pk)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while signing transaction")
+	}
+	return signed, nil
+}
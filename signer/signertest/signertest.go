@@ -0,0 +1,143 @@
+// Package signertest provides a fake remote signer for tests
+package signertest
+
+import (
+	"crypto/ecdsa"
+	"net/http/httptest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// FakeServer is an in-process Web3Signer compatible endpoint signing with local keys
+type FakeServer struct {
+	rpc  *rpc.Server
+	http *httptest.Server
+}
+
+// NewFakeServer starts a fake signer holding given keys
+func NewFakeServer(pks ...*ecdsa.PrivateKey) (*FakeServer, error) {
+	service := &fakeService{keys: make(map[common.Address]*ecdsa.PrivateKey)}
+	for _, pk := range pks {
+		address := crypto.PubkeyToAddress(pk.PublicKey)
+		service.keys[address] = pk
+		service.accounts = append(service.accounts, address)
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		return nil, errors.Wrap(err, "Error while registering fake signer")
+	}
+	return &FakeServer{rpc: server, http: httptest.NewServer(server)}, nil
+}
+
+// URL returns the endpoint of the fake signer
+func (f *FakeServer) URL() string {
+	return f.http.URL
+}
+
+// Close stops the fake signer
+func (f *FakeServer) Close() {
+	f.http.Close()
+	f.rpc.Stop()
+}
+
+// txArgs is the transaction object of eth_signTransaction
+type txArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// fakeService implements the eth namespace of the fake signer
+type fakeService struct {
+	keys     map[common.Address]*ecdsa.PrivateKey
+	accounts []common.Address
+}
+
+func (s *fakeService) Accounts() []common.Address {
+	return s.accounts
+}
+
+func (s *fakeService) Sign(address common.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	pk, err := s.key(address)
+	if err != nil {
+		return nil, err
+	}
+	return signer.SignText(pk, data)
+}
+
+func (s *fakeService) SignTypedData(address common.Address, data apitypes.TypedData) (hexutil.Bytes, error) {
+	pk, err := s.key(address)
+	if err != nil {
+		return nil, err
+	}
+	return signer.SignTypedData(pk, data)
+}
+
+func (s *fakeService) SignTransaction(args txArgs) (hexutil.Bytes, error) {
+	pk, err := s.key(args.From)
+	if err != nil {
+		return nil, err
+	}
+	if args.ChainID == nil || args.Value == nil {
+		return nil, errors.New("Missing chainId or value")
+	}
+
+	var inner types.TxData
+	if args.MaxFeePerGas != nil {
+		if args.MaxPriorityFeePerGas == nil {
+			return nil, errors.New("Missing maxPriorityFeePerGas")
+		}
+		inner = &types.DynamicFeeTx{
+			ChainID:   args.ChainID.ToInt(),
+			Nonce:     uint64(args.Nonce),
+			GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: args.MaxFeePerGas.ToInt(),
+			Gas:       uint64(args.Gas),
+			To:        args.To,
+			Value:     args.Value.ToInt(),
+			Data:      args.Data,
+		}
+	} else {
+		if args.GasPrice == nil {
+			return nil, errors.New("Missing gasPrice")
+		}
+		inner = &types.LegacyTx{
+			Nonce:    uint64(args.Nonce),
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       args.To,
+			Value:    args.Value.ToInt(),
+			Data:     args.Data,
+		}
+	}
+
+	signed, err := types.SignNewTx(pk, types.LatestSignerForChainID(args.ChainID.ToInt()), inner)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+func (s *fakeService) key(address common.Address) (*ecdsa.PrivateKey, error) {
+	pk, ok := s.keys[address]
+	if !ok {
+		return nil, errors.Errorf("No key for %s", address.Hex())
+	}
+	return pk, nil
+}
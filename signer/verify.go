@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidSignature is returned when a signature was not made by the expected address
+var ErrInvalidSignature = errors.New("Invalid signature")
+
+// Recover returns the address that signed digest. Both 27/28 and 0/1 recovery
+// ids are accepted.
+func Recover(digest common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != SignatureLength {
+		return common.Address{}, errors.Errorf("Invalid signature length %d, expected %d", len(sig), SignatureLength)
+	}
+
+	normalized := make([]byte, SignatureLength)
+	copy(normalized, sig)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	if normalized[crypto.RecoveryIDOffset] > 1 {
+		return common.Address{}, errors.Errorf("Invalid signature recovery id %d", sig[crypto.RecoveryIDOffset])
+	}
+
+	// ECDSA.recover rejects malleable signatures with s in the upper half of the curve order
+	if !crypto.ValidateSignatureValues(normalized[crypto.RecoveryIDOffset], common.BytesToHash(normalized[:32]).Big(), common.BytesToHash(normalized[32:64]).Big(), true) {
+		return common.Address{}, errors.New("Invalid signature values")
+	}
+
+	pub, err := crypto.SigToPub(digest.Bytes(), normalized)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "Error while recovering signer")
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks that sig over digest was made by expected
+func Verify(digest common.Hash, sig []byte, expected common.Address) error {
+	signer, err := Recover(digest, sig)
+	if err != nil {
+		return err
+	}
+	if signer != expected {
+		return errors.Wrapf(ErrInvalidSignature, "signed by %s, expected %s", signer.Hex(), expected.Hex())
+	}
+	return nil
+}
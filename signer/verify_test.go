@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// secp256k1n is the order of the secp256k1 curve
+var secp256k1n = crypto.S256().Params().N
+
+func TestRecover(t *testing.T) {
+	v := vectors[0]
+	digest, _ := v.digest()
+	sig := hexutil.MustDecode(v.signature)
+
+	// The same signature with s replaced by n - s and the recovery id flipped
+	// recovers to the same key, ECDSA.recover rejects it
+	malleable := make([]byte, SignatureLength)
+	copy(malleable, sig)
+	s := new(big.Int).Sub(secp256k1n, new(big.Int).SetBytes(sig[32:64]))
+	s.FillBytes(malleable[32:64])
+	malleable[64] ^= 1
+
+	tests := []struct {
+		name    string
+		sig     func() []byte
+		wantErr bool
+	}{
+		{name: "v 27/28", sig: func() []byte { return sig }},
+		{name: "v 0/1", sig: func() []byte {
+			out := append([]byte{}, sig...)
+			out[64] -= 27
+			return out
+		}},
+		{name: "v out of range", sig: func() []byte {
+			out := append([]byte{}, sig...)
+			out[64] = 29
+			return out
+		}, wantErr: true},
+		{name: "v 2", sig: func() []byte {
+			out := append([]byte{}, sig...)
+			out[64] = 2
+			return out
+		}, wantErr: true},
+		{name: "malleable high s", sig: func() []byte { return malleable }, wantErr: true},
+		{name: "short", sig: func() []byte { return sig[:64] }, wantErr: true},
+		{name: "zero r", sig: func() []byte {
+			out := append([]byte{}, sig...)
+			clear(out[:32])
+			return out
+		}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Recover(digest, tt.sig())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Recover = %s, want error", got.Hex())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Recover: %v", err)
+			}
+			if got != v.address {
+				t.Fatalf("Recover = %s, want %s", got.Hex(), v.address.Hex())
+			}
+		})
+	}
+}
+
+func TestRecoverDoesNotModifySignature(t *testing.T) {
+	v := vectors[0]
+	digest, _ := v.digest()
+	sig := hexutil.MustDecode(v.signature)
+	if _, err := Recover(digest, sig); err != nil {
+		t.Fatal(err)
+	}
+	if hexutil.Encode(sig) != v.signature {
+		t.Fatalf("signature changed to %s", hexutil.Encode(sig))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	v := vectors[0]
+	digest, _ := v.digest()
+	sig := hexutil.MustDecode(v.signature)
+
+	if err := Verify(digest, sig, v.address); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := Verify(digest, sig, common.HexToAddress("0x01")); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Verify with another address = %v, want ErrInvalidSignature", err)
+	}
+	other := TextHash([]byte("Other data"))
+	if err := Verify(other, sig, v.address); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Verify of another digest = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,127 @@
+package stake
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/abis/stakeregistry"
+	"github.com/patiee/avs-go-operator/chain"
+	"github.com/patiee/avs-go-operator/signer"
+	"github.com/patiee/avs-go-operator/txmgr"
+)
+
+// Service for the ECDSA stake registry of the AVS
+type Service struct {
+	chainID         *big.Int
+	registry        *stakeregistry.ECDSAStakeRegistry
+	registryAddress common.Address
+	client          *chain.Client
+	logger          *log.Logger
+	txm             *txmgr.Manager
+}
+
+// New returns a new stake registry Service
+func New(client *chain.Client, logger *log.Logger, txm *txmgr.Manager, registryAddress common.Address) (*Service, error) {
+	chainID, err := client.NetworkID(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting network id")
+	}
+
+	registry, err := stakeregistry.NewECDSAStakeRegistry(registryAddress, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while creating stake registry")
+	}
+
+	return &Service{
+		chainID:         chainID,
+		registry:        registry,
+		registryAddress: registryAddress,
+		client:          client,
+		logger:          logger,
+		txm:             txm,
+	}, nil
+}
+
+// Address returns the address of the stake registry
+func (s *Service) Address() common.Address {
+	return s.registryAddress
+}
+
+// SigningKey returns the current signing key of operator, the zero address when it has none
+func (s *Service) SigningKey(ctx context.Context, operator common.Address) (common.Address, error) {
+	key, err := s.registry.GetLastestOperatorSigningKey(&bind.CallOpts{Context: ctx}, operator)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "Error while getting operator signing key")
+	}
+	return key, nil
+}
+
+// SigningKeyAt returns the signing key of operator in effect at block
+func (s *Service) SigningKeyAt(ctx context.Context, operator common.Address, block uint64) (common.Address, error) {
+	key, err := s.registry.GetOperatorSigningKeyAtBlock(&bind.CallOpts{Context: ctx}, operator, new(big.Int).SetUint64(block))
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "Error while getting operator signing key at block %d", block)
+	}
+	return key, nil
+}
+
+// UpdateSigningKey makes newKey the signing key of operator from the block
+// the transaction is mined in on
+func (s *Service) UpdateSigningKey(ctx context.Context, operator signer.Signer, newKey common.Address) (*txmgr.Result, error) {
+	result, err := s.txm.SendAs(ctx, operator, s.chainID, "updateOperatorSigningKey", func(transactor *bind.TransactOpts) (*types.Transaction, error) {
+		return s.registry.UpdateOperatorSigningKey(transactor, newKey)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while updating signing key")
+	}
+	return result, nil
+}
+
+// WatchSigningKeyUpdates polls for signing key updates of operator from block
+// from on and calls fn for each of them in order until ctx is cancelled.
+// Polling with log filters survives reconnects of the client.
+func (s *Service) WatchSigningKeyUpdates(ctx context.Context, operator common.Address, from uint64, interval time.Duration, fn func(*stakeregistry.ECDSAStakeRegistrySigningKeyUpdate)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		head, err := s.client.BlockNumber(ctx)
+		if err != nil {
+			s.logger.Printf("Error while getting block number for signing key updates: %v\n", err)
+		} else if head >= from {
+			if err := s.SigningKeyUpdates(ctx, operator, from, head, fn); err != nil {
+				s.logger.Printf("Error while getting signing key updates: %v\n", err)
+			} else {
+				from = head + 1
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SigningKeyUpdates calls fn for each signing key update of operator in
+// blocks [from, to] in order
+func (s *Service) SigningKeyUpdates(ctx context.Context, operator common.Address, from, to uint64, fn func(*stakeregistry.ECDSAStakeRegistrySigningKeyUpdate)) error {
+	it, err := s.registry.FilterSigningKeyUpdate(&bind.FilterOpts{Start: from, End: &to, Context: ctx}, []common.Address{operator}, nil, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error while filtering signing key updates")
+	}
+	defer it.Close()
+
+	for it.Next() {
+		fn(it.Event)
+	}
+	return it.Error()
+}
@@ -0,0 +1,60 @@
+package txmgr
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Record is a single entry of the transaction journal
+type Record struct {
+	Time       time.Time `json:"time"`
+	Label      string    `json:"label"`
+	Attempt    int       `json:"attempt"`
+	Hash       string    `json:"hash,omitempty"`
+	Nonce      uint64    `json:"nonce"`
+	Status     Status    `json:"status"`
+	Reason     string    `json:"reason,omitempty"`
+	ReplacedBy string    `json:"replacedBy,omitempty"`
+	Block      uint64    `json:"block,omitempty"`
+	GasUsed    uint64    `json:"gasUsed,omitempty"`
+}
+
+// Journal appends transaction records to a JSON lines file
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenJournal opens or creates the journal file at given path
+func OpenJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while opening transaction journal")
+	}
+	return &Journal{file: file}, nil
+}
+
+// Append writes a record to the journal
+func (j *Journal) Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "Error while encoding journal record")
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "Error while writing journal record")
+	}
+	return nil
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
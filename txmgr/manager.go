@@ -0,0 +1,250 @@
+package txmgr
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/patiee/avs-go-operator/fees"
+	"github.com/patiee/avs-go-operator/gas"
+	"github.com/patiee/avs-go-operator/nonce"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// Status of a sent transaction
+type Status string
+
+const (
+	// Failed means the transaction never reached the node
+	Failed Status = "failed"
+	// Sent means the transaction was accepted by the node
+	Sent Status = "sent"
+	// Mined means the transaction was included and succeeded
+	Mined Status = "mined"
+	// Reverted means the transaction was included and reverted
+	Reverted Status = "reverted"
+	// Dropped means the node no longer knows the transaction
+	Dropped Status = "dropped"
+	// Stuck means the transaction is still pending after the receipt timeout
+	Stuck Status = "stuck"
+	// Replaced means the transaction was re-signed with a higher fee
+	Replaced Status = "replaced"
+)
+
+// Backend is the part of the Ethereum client the Manager needs
+type Backend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// RetryPolicy decides which failed transactions are sent again
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each next one
+	Backoff time.Duration
+	// RetryReverted sends reverted transactions again, otherwise they are final
+	RetryReverted bool
+}
+
+func (p RetryPolicy) retry(status Status) bool {
+	switch status {
+	case Failed, Dropped:
+		return true
+	case Reverted:
+		return p.RetryReverted
+	default:
+		return false
+	}
+}
+
+// Config configures the Manager
+type Config struct {
+	// ReceiptTimeout is how long to wait for a receipt, including replacements
+	ReceiptTimeout time.Duration
+	PollInterval   time.Duration
+	Retry          RetryPolicy
+	Replace        ReplacePolicy
+}
+
+// Result is the final outcome of a transaction
+type Result struct {
+	Tx      *types.Transaction
+	Receipt *types.Receipt
+	Status  Status
+	Reason  string
+}
+
+// Manager sends transactions, waits for their receipts, replaces stuck ones
+// and retries failures. It owns nonce allocation for every transaction it sends.
+type Manager struct {
+	backend Backend
+	nonces  *nonce.Manager
+	fees    fees.Strategy
+	gas     *gas.Estimator
+	journal *Journal
+	logger  *log.Logger
+	cfg     Config
+
+	mu      sync.Mutex
+	pending map[pendingKey]*types.Transaction
+}
+
+type pendingKey struct {
+	from  common.Address
+	nonce uint64
+}
+
+// New returns a new transaction Manager
+func New(backend Backend, nonces *nonce.Manager, strategy fees.Strategy, estimator *gas.Estimator, journal *Journal, logger *log.Logger, cfg Config) *Manager {
+	return &Manager{
+		backend: backend,
+		nonces:  nonces,
+		fees:    strategy,
+		gas:     estimator,
+		journal: journal,
+		logger:  logger,
+		cfg:     cfg,
+		pending: make(map[pendingKey]*types.Transaction),
+	}
+}
+
+// Send builds a transaction with fn using a nonce from the nonce manager,
+// fees from the fee strategy and a gas limit from the estimator. It waits for
+// the transaction to be mined, replacing it with a higher fee while it is stuck.
+// Failed transactions are built again with a fresh nonce when the retry policy
+// allows. An error is returned only when no transaction reached a final status.
+func (m *Manager) Send(ctx context.Context, label string, opts *bind.TransactOpts, fn func(*bind.TransactOpts) (*types.Transaction, error)) (*Result, error) {
+	if m.cfg.Retry.MaxAttempts < 1 {
+		return nil, errors.Errorf("Error while sending %s: at least one attempt is required, max attempts is %d", label, m.cfg.Retry.MaxAttempts)
+	}
+
+	var lastErr error
+	var result *Result
+
+	for attempt := 1; attempt <= m.cfg.Retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := m.cfg.Retry.Backoff << (attempt - 2)
+			m.logger.Printf("Retrying %s in %s, attempt %d of %d\n", label, delay, attempt, m.cfg.Retry.MaxAttempts)
+			if err := sleep(ctx, delay); err != nil {
+				return result, err
+			}
+		}
+
+		// Fees are priced again for every attempt
+		if err := m.fees.Apply(ctx, opts); err != nil {
+			lastErr = err
+			m.record(Record{Label: label, Attempt: attempt, Status: Failed, Reason: err.Error()})
+			m.logger.Printf("Error while pricing %s: %v\n", label, err)
+			continue
+		}
+
+		tx, err := m.nonces.Send(ctx, opts.From, func(nonce uint64) (*types.Transaction, error) {
+			opts.Nonce = new(big.Int).SetUint64(nonce)
+			if err := m.gas.Apply(ctx, opts, fn); err != nil {
+				return nil, err
+			}
+			return fn(opts)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			lastErr = err
+			m.record(Record{Label: label, Attempt: attempt, Status: Failed, Reason: err.Error()})
+			m.logger.Printf("Error while sending %s: %v\n", label, err)
+			continue
+		}
+
+		m.logger.Printf("Sent %s, tx hash: %s\n", label, tx.Hash().Hex())
+		m.record(Record{Label: label, Attempt: attempt, Hash: tx.Hash().Hex(), Nonce: tx.Nonce(), Status: Sent})
+
+		result, err = m.wait(ctx, label, attempt, opts, tx)
+		if err != nil {
+			return result, err
+		}
+		m.report(label, attempt, result)
+
+		if result.Status == Dropped {
+			// The dropped nonce would otherwise hold up every later transaction
+			m.nonces.Resync(opts.From)
+		}
+		if !m.cfg.Retry.retry(result.Status) {
+			return result, nil
+		}
+	}
+
+	if result != nil {
+		return result, nil
+	}
+	return nil, errors.Wrapf(lastErr, "Error while sending %s after %d attempts", label, m.cfg.Retry.MaxAttempts)
+}
+
+// SendAs sends the transaction built by fn like Send, signed by account for chainID
+func (m *Manager) SendAs(ctx context.Context, account signer.Signer, chainID *big.Int, label string, fn func(*bind.TransactOpts) (*types.Transaction, error)) (*Result, error) {
+	return m.Send(ctx, label, signer.TransactOpts(ctx, account, chainID), fn)
+}
+
+// Pending returns the latest transaction sent for every nonce that is not final yet
+func (m *Manager) Pending() []*types.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending := make([]*types.Transaction, 0, len(m.pending))
+	for _, tx := range m.pending {
+		pending = append(pending, tx)
+	}
+	return pending
+}
+
+func (m *Manager) report(label string, attempt int, result *Result) {
+	tx := result.Tx
+	record := Record{Label: label, Attempt: attempt, Hash: tx.Hash().Hex(), Nonce: tx.Nonce(), Status: result.Status, Reason: result.Reason}
+	if result.Receipt != nil {
+		record.Block = result.Receipt.BlockNumber.Uint64()
+		record.GasUsed = result.Receipt.GasUsed
+	}
+	m.record(record)
+
+	switch result.Status {
+	case Mined:
+		m.logger.Printf("Mined %s in block %d, tx hash: %s\n", label, record.Block, tx.Hash().Hex())
+	case Reverted:
+		m.logger.Printf("Reverted %s in block %d: %s, tx hash: %s\n", label, record.Block, result.Reason, tx.Hash().Hex())
+	default:
+		m.logger.Printf("Transaction for %s is %s, tx hash: %s\n", label, result.Status, tx.Hash().Hex())
+	}
+}
+
+func (m *Manager) record(record Record) {
+	if m.journal == nil {
+		return
+	}
+	record.Time = time.Now()
+	if err := m.journal.Append(record); err != nil {
+		m.logger.Printf("Error while recording transaction: %v\n", err)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,101 @@
+package txmgr
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/patiee/avs-go-operator/gas"
+	"github.com/patiee/avs-go-operator/nonce"
+	"github.com/patiee/avs-go-operator/signer"
+)
+
+// minedBackend mines every transaction it is sent right away
+type minedBackend struct {
+	*testBackend
+}
+
+func (b minedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1), TxHash: tx.Hash()}
+	return b.testBackend.SendTransaction(ctx, tx)
+}
+
+func (b minedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 7, nil
+}
+
+func (b minedBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func TestSendAs(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := signer.NewLocal(pk)
+	chainID := big.NewInt(17000)
+
+	backend := minedBackend{newTestBackend()}
+	logger := log.New(io.Discard, "", 0)
+	m := New(backend, nonce.New(backend, logger), fixedFees{feeCap: big.NewInt(10), tip: big.NewInt(1)}, gas.New(backend, logger, gas.Config{Multiplier: 1}), nil, logger, Config{
+		ReceiptTimeout: time.Second,
+		PollInterval:   5 * time.Millisecond,
+		Retry:          RetryPolicy{MaxAttempts: 1},
+	})
+
+	to := common.HexToAddress("0x01")
+	result, err := m.SendAs(context.Background(), account, chainID, "transfer", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		tx, err := opts.Signer(opts.From, types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: opts.Nonce.Uint64(), GasTipCap: opts.GasTipCap, GasFeeCap: opts.GasFeeCap, Gas: opts.GasLimit, To: &to,
+		}))
+		if err != nil || opts.NoSend {
+			return tx, err
+		}
+		return tx, backend.SendTransaction(opts.Context, tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != Mined {
+		t.Fatalf("SendAs = %s, want mined", result.Status)
+	}
+
+	tx := result.Tx
+	from, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != account.Address() {
+		t.Errorf("sender = %s, want %s", from.Hex(), account.Address().Hex())
+	}
+	if tx.ChainId().Cmp(chainID) != 0 || tx.Nonce() != 7 || tx.Gas() != 21000 {
+		t.Errorf("tx chain id %s nonce %d gas %d, want 17000, 7 and 21000", tx.ChainId(), tx.Nonce(), tx.Gas())
+	}
+}
+
+func TestSendWithoutAttempts(t *testing.T) {
+	backend := minedBackend{newTestBackend()}
+	m := New(backend, nil, nil, nil, nil, log.New(io.Discard, "", 0), Config{})
+
+	built := false
+	result, err := m.Send(context.Background(), "transfer", &bind.TransactOpts{}, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		built = true
+		return nil, nil
+	})
+	if err == nil || result != nil {
+		t.Fatalf("Send = %v, %v, want an error", result, err)
+	}
+	if built {
+		t.Error("Send built a transaction without any attempt allowed")
+	}
+}
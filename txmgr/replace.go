@@ -0,0 +1,167 @@
+package txmgr
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+)
+
+// ReplacePolicy configures replacement of stuck transactions
+type ReplacePolicy struct {
+	// After is how long a transaction may stay pending before it is replaced,
+	// zero disables replacement
+	After time.Duration
+	// BumpPercent is the fee increase of each replacement, nodes reject
+	// replacements that bump the fee by less than 10 percent
+	BumpPercent int64
+	// MaxGasPrice caps the gas price, or the fee cap of dynamic fee
+	// transactions, nil means no cap
+	MaxGasPrice *big.Int
+}
+
+var errFeeCapReached = errors.New("Fee cap reached")
+
+// replace re-signs tx with the same nonce and a bumped fee and sends it
+func (m *Manager) replace(ctx context.Context, opts *bind.TransactOpts, tx *types.Transaction) (*types.Transaction, error) {
+	inner, err := m.bump(tx, tx.To(), tx.Value(), tx.Gas(), tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	return m.signAndSend(ctx, opts, inner)
+}
+
+// Cancel replaces the pending transaction of opts.From at nonce with a
+// zero-value transfer to itself and waits for one of them to be mined
+func (m *Manager) Cancel(ctx context.Context, opts *bind.TransactOpts, nonce uint64) (*Result, error) {
+	m.mu.Lock()
+	prev := m.pending[pendingKey{from: opts.From, nonce: nonce}]
+	m.mu.Unlock()
+
+	if prev == nil {
+		// Not sent by this process, outbid the current fees instead
+		var err error
+		if prev, err = m.priced(ctx, opts, nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	to := opts.From
+	inner, err := m.bump(prev, &to, new(big.Int), params.TxGas, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := m.signAndSend(ctx, opts, inner)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while cancelling nonce %d", nonce)
+	}
+
+	label := fmt.Sprintf("cancel of nonce %d", nonce)
+	m.logger.Printf("Sent %s, tx hash: %s\n", label, tx.Hash().Hex())
+	m.record(Record{Label: label, Attempt: 1, Hash: tx.Hash().Hex(), Nonce: nonce, Status: Sent})
+
+	result, err := m.wait(ctx, label, 1, opts, tx)
+	if err != nil {
+		return nil, err
+	}
+	m.report(label, 1, result)
+	return result, nil
+}
+
+// priced returns an empty transaction at nonce with fees from the fee strategy
+func (m *Manager) priced(ctx context.Context, opts *bind.TransactOpts, nonce uint64) (*types.Transaction, error) {
+	priced := *opts
+	if err := m.fees.Apply(ctx, &priced); err != nil {
+		return nil, err
+	}
+	if priced.GasFeeCap == nil {
+		return types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: priced.GasPrice}), nil
+	}
+
+	chainID, err := m.backend.ChainID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while getting chain id")
+	}
+	return types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: nonce, GasTipCap: priced.GasTipCap, GasFeeCap: priced.GasFeeCap}), nil
+}
+
+// bump returns a copy of tx with given call fields and fees raised by the bump percentage
+func (m *Manager) bump(tx *types.Transaction, to *common.Address, value *big.Int, gas uint64, data []byte) (types.TxData, error) {
+	if tx.Type() == types.DynamicFeeTxType {
+		feeCap, err := m.bumpFee(tx.GasFeeCap())
+		if err != nil {
+			return nil, err
+		}
+		tip := bumpPercent(tx.GasTipCap(), m.cfg.Replace.BumpPercent)
+		if tip.Cmp(feeCap) > 0 {
+			tip = feeCap
+		}
+
+		return &types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tip,
+			GasFeeCap:  feeCap,
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: tx.AccessList(),
+		}, nil
+	}
+
+	gasPrice, err := m.bumpFee(tx.GasPrice())
+	if err != nil {
+		return nil, err
+	}
+	return &types.LegacyTx{
+		Nonce:    tx.Nonce(),
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       to,
+		Value:    value,
+		Data:     data,
+	}, nil
+}
+
+// bumpFee raises fee by the bump percentage without going over the cap
+func (m *Manager) bumpFee(fee *big.Int) (*big.Int, error) {
+	max := m.cfg.Replace.MaxGasPrice
+	if max != nil && max.Sign() > 0 && fee.Cmp(max) >= 0 {
+		return nil, errFeeCapReached
+	}
+
+	bumped := bumpPercent(fee, m.cfg.Replace.BumpPercent)
+	if max != nil && max.Sign() > 0 && bumped.Cmp(max) > 0 {
+		bumped = new(big.Int).Set(max)
+	}
+	return bumped, nil
+}
+
+func (m *Manager) signAndSend(ctx context.Context, opts *bind.TransactOpts, inner types.TxData) (*types.Transaction, error) {
+	signed, err := opts.Signer(opts.From, types.NewTx(inner))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error while signing transaction")
+	}
+	if err := m.backend.SendTransaction(ctx, signed); err != nil {
+		return nil, errors.Wrap(err, "Error while sending transaction")
+	}
+	return signed, nil
+}
+
+// bumpPercent returns value raised by percent, and by at least one wei
+func bumpPercent(value *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(value, big.NewInt(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(value) <= 0 {
+		bumped.Add(value, big.NewInt(1))
+	}
+	return bumped
+}
@@ -0,0 +1,133 @@
+package txmgr
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+)
+
+func TestBumpPercent(t *testing.T) {
+	tests := []struct {
+		value   int64
+		percent int64
+		want    int64
+	}{
+		{value: 100, percent: 10, want: 110},
+		{value: 1000, percent: 15, want: 1150},
+		{value: 5, percent: 10, want: 6},
+		{value: 0, percent: 10, want: 1},
+	}
+	for _, tt := range tests {
+		if got := bumpPercent(big.NewInt(tt.value), tt.percent); got.Int64() != tt.want {
+			t.Fatalf("bumpPercent(%d, %d) = %v, want %d", tt.value, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		fee     int64
+		max     *big.Int
+		want    int64
+		wantErr error
+	}{
+		{name: "no cap", fee: 100, want: 115},
+		{name: "zero cap", fee: 100, max: new(big.Int), want: 115},
+		{name: "below cap", fee: 100, max: big.NewInt(200), want: 115},
+		{name: "clamped to cap", fee: 100, max: big.NewInt(110), want: 110},
+		{name: "at cap", fee: 110, max: big.NewInt(110), wantErr: errFeeCapReached},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manager{cfg: Config{Replace: ReplacePolicy{BumpPercent: 15, MaxGasPrice: tt.max}}}
+			got, err := m.bumpFee(big.NewInt(tt.fee))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("bumpFee error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Int64() != tt.want {
+				t.Fatalf("bumpFee = %v, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	m := &Manager{cfg: Config{Replace: ReplacePolicy{BumpPercent: 10, MaxGasPrice: big.NewInt(105)}}}
+
+	// The tip follows the bump but never exceeds the capped fee cap
+	dynamic := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(17000), Nonce: 4, GasTipCap: big.NewInt(100), GasFeeCap: big.NewInt(100), Gas: 50000, To: &to, Data: []byte{0x01}})
+	inner, err := m.bump(dynamic, dynamic.To(), dynamic.Value(), dynamic.Gas(), dynamic.Data())
+	if err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	bumped := types.NewTx(inner)
+	if bumped.Nonce() != 4 || bumped.GasFeeCap().Int64() != 105 || bumped.GasTipCap().Int64() != 105 || bumped.ChainId().Int64() != 17000 {
+		t.Fatalf("bump = nonce %d, fee cap %v, tip %v, chain %v", bumped.Nonce(), bumped.GasFeeCap(), bumped.GasTipCap(), bumped.ChainId())
+	}
+	if bumped.Gas() != 50000 || *bumped.To() != to || len(bumped.Data()) != 1 {
+		t.Fatal("bump changed the call")
+	}
+
+	legacy := types.NewTx(&types.LegacyTx{Nonce: 4, GasPrice: big.NewInt(50), Gas: 50000, To: &to})
+	if inner, err = m.bump(legacy, legacy.To(), legacy.Value(), legacy.Gas(), legacy.Data()); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	if bumped := types.NewTx(inner); bumped.Type() != types.LegacyTxType || bumped.GasPrice().Int64() != 55 {
+		t.Fatalf("bump = type %d, gas price %v, want legacy at 55", bumped.Type(), bumped.GasPrice())
+	}
+}
+
+// Cancelling a nonce this process did not send outbids the fee strategy
+func TestCancelUnknownNonce(t *testing.T) {
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(17000)
+	opts, err := bind.NewKeyedTransactorWithChainID(pk, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := newTestBackend()
+	m := newTestManager(backend)
+	m.cfg.Replace.BumpPercent = 10
+	m.fees = fixedFees{feeCap: big.NewInt(100), tip: big.NewInt(10)}
+
+	result, err := m.Cancel(context.Background(), opts, 7)
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if len(backend.sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(backend.sent))
+	}
+	tx := backend.sent[0]
+	if tx.Nonce() != 7 || *tx.To() != opts.From || tx.Value().Sign() != 0 || tx.Gas() != params.TxGas {
+		t.Fatalf("cancel = nonce %d to %s value %v gas %d, want a transfer to itself", tx.Nonce(), tx.To().Hex(), tx.Value(), tx.Gas())
+	}
+	if tx.GasFeeCap().Int64() != 110 || tx.GasTipCap().Int64() != 11 {
+		t.Fatalf("cancel fees = %v / %v, want 110 / 11", tx.GasFeeCap(), tx.GasTipCap())
+	}
+	if result.Status != Dropped {
+		t.Fatalf("Cancel = %s, want dropped with an empty backend", result.Status)
+	}
+}
+
+type fixedFees struct {
+	feeCap *big.Int
+	tip    *big.Int
+}
+
+func (f fixedFees) Apply(ctx context.Context, opts *bind.TransactOpts) error {
+	opts.GasPrice, opts.GasFeeCap, opts.GasTipCap = nil, f.feeCap, f.tip
+	return nil
+}
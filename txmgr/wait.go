@@ -0,0 +1,158 @@
+package txmgr
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// Wait polls for the receipt of tx until the receipt timeout and classifies
+// the outcome, without replacing the transaction
+func (m *Manager) Wait(ctx context.Context, tx *types.Transaction) (*Result, error) {
+	return m.wait(ctx, "", 0, nil, tx)
+}
+
+// wait polls for the receipt of tx until the receipt timeout. When opts is set
+// a transaction pending for longer than the replace timeout is re-signed with
+// the same nonce and a bumped fee. Any of the sent versions may end up mined.
+func (m *Manager) wait(ctx context.Context, label string, attempt int, opts *bind.TransactOpts, tx *types.Transaction) (*Result, error) {
+	key := pendingKey{nonce: tx.Nonce()}
+	if opts != nil {
+		key.from = opts.From
+		m.track(key, tx)
+		defer m.untrack(key)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, m.cfg.ReceiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	sent := []*types.Transaction{tx}
+	lastSent := time.Now()
+	capped := false
+
+	for {
+		for _, candidate := range sent {
+			receipt, err := m.backend.TransactionReceipt(waitCtx, candidate.Hash())
+			if err == nil {
+				return m.included(ctx, candidate, receipt), nil
+			}
+			if !errors.Is(err, ethereum.NotFound) && waitCtx.Err() == nil {
+				m.logger.Printf("Error while getting receipt of %s: %v\n", candidate.Hash().Hex(), err)
+			}
+		}
+
+		latest := sent[len(sent)-1]
+		if opts != nil && !capped && m.cfg.Replace.After > 0 && time.Since(lastSent) >= m.cfg.Replace.After {
+			replacement, err := m.replace(waitCtx, opts, latest)
+			switch {
+			case errors.Is(err, errFeeCapReached):
+				m.logger.Printf("Not replacing %s, fee cap reached\n", label)
+				capped = true
+			case err != nil:
+				m.logger.Printf("Error while replacing %s: %v\n", label, err)
+			default:
+				m.logger.Printf("Replaced %s, tx hash: %s\n", label, replacement.Hash().Hex())
+				m.record(Record{Label: label, Attempt: attempt, Hash: latest.Hash().Hex(), Nonce: latest.Nonce(), Status: Replaced, ReplacedBy: replacement.Hash().Hex()})
+				sent = append(sent, replacement)
+				m.track(key, replacement)
+			}
+			lastSent = time.Now()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return m.classify(ctx, sent)
+		}
+	}
+}
+
+// classify tells transactions still in the mempool from ones the node forgot about
+func (m *Manager) classify(ctx context.Context, sent []*types.Transaction) (*Result, error) {
+	latest := sent[len(sent)-1]
+	known := false
+
+	for _, tx := range sent {
+		_, pending, err := m.backend.TransactionByHash(ctx, tx.Hash())
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error while getting transaction %s", tx.Hash().Hex())
+		}
+		known = true
+
+		if !pending {
+			// Mined right after the timeout, the receipt should be there now
+			if receipt, err := m.backend.TransactionReceipt(ctx, tx.Hash()); err == nil {
+				return m.included(ctx, tx, receipt), nil
+			}
+		}
+	}
+
+	if !known {
+		return &Result{Tx: latest, Status: Dropped}, nil
+	}
+	return &Result{Tx: latest, Status: Stuck}, nil
+}
+
+// included classifies a transaction that has a receipt
+func (m *Manager) included(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) *Result {
+	result := &Result{Tx: tx, Receipt: receipt, Status: Mined}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		result.Status = Reverted
+		result.Reason = m.revertReason(ctx, tx, receipt)
+	}
+	return result
+}
+
+// revertReason replays a reverted transaction as a call to recover its revert reason
+func (m *Manager) revertReason(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) string {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return "unknown"
+	}
+
+	msg := ethereum.CallMsg{From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data()}
+	_, err = m.backend.CallContract(ctx, msg, receipt.BlockNumber)
+	if err == nil {
+		return "unknown"
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if raw, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(raw); unpackErr == nil {
+					return reason
+				}
+			}
+		}
+	}
+	return err.Error()
+}
+
+func (m *Manager) track(key pendingKey, tx *types.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending[key] = tx
+}
+
+func (m *Manager) untrack(key pendingKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pending, key)
+}
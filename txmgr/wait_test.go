@@ -0,0 +1,216 @@
+package txmgr
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// testBackend knows the receipts and mempool state of transactions by hash
+type testBackend struct {
+	receipts map[common.Hash]*types.Receipt
+	pending  map[common.Hash]bool
+	callErr  error
+	sent     []*types.Transaction
+}
+
+func newTestBackend() *testBackend {
+	return &testBackend{receipts: make(map[common.Hash]*types.Receipt), pending: make(map[common.Hash]bool)}
+}
+
+func (b *testBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if receipt, ok := b.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *testBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if pending, ok := b.pending[hash]; ok {
+		return nil, pending, nil
+	}
+	if _, ok := b.receipts[hash]; ok {
+		return nil, false, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func (b *testBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, b.callErr
+}
+
+func (b *testBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *testBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(17000), nil
+}
+
+// revertError is an RPC error carrying revert data, like the one of eth_call
+type revertError struct {
+	data string
+}
+
+func (e revertError) Error() string          { return "execution reverted" }
+func (e revertError) ErrorCode() int         { return 3 }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+func newTestManager(backend Backend) *Manager {
+	return New(backend, nil, nil, nil, nil, log.New(io.Discard, "", 0), Config{
+		ReceiptTimeout: 50 * time.Millisecond,
+		PollInterval:   5 * time.Millisecond,
+	})
+}
+
+func signedTx(t *testing.T, nonce uint64) *types.Transaction {
+	t.Helper()
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x01")
+	tx, err := types.SignNewTx(pk, types.LatestSignerForChainID(big.NewInt(17000)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(17000), Nonce: nonce, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(10), Gas: 21000, To: &to,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		status        Status
+		retryReverted bool
+		want          bool
+	}{
+		{status: Failed, want: true},
+		{status: Dropped, want: true},
+		{status: Reverted},
+		{status: Reverted, retryReverted: true, want: true},
+		{status: Mined, retryReverted: true},
+		{status: Stuck, retryReverted: true},
+	}
+	for _, tt := range tests {
+		policy := RetryPolicy{RetryReverted: tt.retryReverted}
+		if got := policy.retry(tt.status); got != tt.want {
+			t.Fatalf("retry(%s) with RetryReverted %v = %v, want %v", tt.status, tt.retryReverted, got, tt.want)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	revertData, err := abi.Arguments{{Type: mustType(t, "string")}}.Pack("Task already responded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revertData = append(crypto.Keccak256([]byte("Error(string)"))[:4], revertData...)
+
+	tests := []struct {
+		name       string
+		setup      func(b *testBackend, tx *types.Transaction)
+		want       Status
+		wantReason string
+	}{
+		{
+			name: "mined",
+			setup: func(b *testBackend, tx *types.Transaction) {
+				b.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1)}
+			},
+			want: Mined,
+		},
+		{
+			name: "reverted with reason",
+			setup: func(b *testBackend, tx *types.Transaction) {
+				b.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(1)}
+				b.callErr = revertError{data: hexutil.Encode(revertData)}
+			},
+			want:       Reverted,
+			wantReason: "Task already responded",
+		},
+		{
+			name: "reverted without data",
+			setup: func(b *testBackend, tx *types.Transaction) {
+				b.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(1)}
+				b.callErr = errors.New("out of gas")
+			},
+			want:       Reverted,
+			wantReason: "out of gas",
+		},
+		{
+			name: "stuck in the mempool",
+			setup: func(b *testBackend, tx *types.Transaction) {
+				b.pending[tx.Hash()] = true
+			},
+			want: Stuck,
+		},
+		{
+			name: "dropped",
+			want: Dropped,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newTestBackend()
+			tx := signedTx(t, 0)
+			if tt.setup != nil {
+				tt.setup(backend, tx)
+			}
+			result, err := newTestManager(backend).Wait(context.Background(), tx)
+			if err != nil {
+				t.Fatalf("Wait: %v", err)
+			}
+			if result.Status != tt.want || result.Reason != tt.wantReason {
+				t.Fatalf("Wait = %s %q, want %s %q", result.Status, result.Reason, tt.want, tt.wantReason)
+			}
+			if result.Tx.Hash() != tx.Hash() {
+				t.Fatalf("Wait returned transaction %s, want %s", result.Tx.Hash().Hex(), tx.Hash().Hex())
+			}
+		})
+	}
+}
+
+// A replacement mined right after the receipt timeout is found by classify
+func TestClassifyMinedReplacement(t *testing.T) {
+	backend := newTestBackend()
+	original, replacement := signedTx(t, 0), signedTx(t, 0)
+	backend.receipts[replacement.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1)}
+
+	result, err := newTestManager(backend).classify(context.Background(), []*types.Transaction{original, replacement})
+	if err != nil {
+		t.Fatalf("classify: %v", err)
+	}
+	if result.Status != Mined || result.Tx.Hash() != replacement.Hash() {
+		t.Fatalf("classify = %s %s, want mined %s", result.Status, result.Tx.Hash().Hex(), replacement.Hash().Hex())
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := newTestManager(newTestBackend()).Wait(ctx, signedTx(t, 0)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait = %v, want context.Canceled", err)
+	}
+}
+
+func mustType(t *testing.T, name string) abi.Type {
+	t.Helper()
+	typ, err := abi.NewType(name, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return typ
+}